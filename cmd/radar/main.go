@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"flag"
 	"os"
@@ -13,31 +12,33 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
-	massivews "github.com/massive-com/client-go/v2/websocket"
-	wsmodels "github.com/massive-com/client-go/v2/websocket/models"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"stockradar/internal/config"
+	"stockradar/internal/feed"
 	"stockradar/internal/radar"
+	"stockradar/internal/recorder"
+	"stockradar/internal/refdata"
+	"stockradar/internal/replay"
 	"stockradar/internal/server"
+	"stockradar/internal/session"
 	"stockradar/internal/tts"
 	"stockradar/internal/watchlist"
 )
 
-type Tick struct {
-	Symbol string
-	Price  float64
-	Volume float64
-	Time   time.Time
-}
-
 func main() {
 	var cfgPath string
 	var wlPath string
+	var replayPath string
+	var replaySpeed float64
+	var replayMaxGap time.Duration
 
 	flag.StringVar(&cfgPath, "config", "config.yaml", "Path to config YAML")
 	flag.StringVar(&wlPath, "watchlist", "watchlist.yaml", "Path to watchlist YAML")
+	flag.StringVar(&replayPath, "replay", "", "Replay recorded ticks from a JSONL file (.gz ok) instead of the live Massive feed")
+	flag.Float64Var(&replaySpeed, "replay-speed", 1, "Replay speed: 1 = real time, N = N times faster, 0 = as fast as possible")
+	flag.DurationVar(&replayMaxGap, "replay-max-gap", 0, "Compress quiet stretches in the replay (overnight, halts) to this long; 0 = keep recorded gaps")
 	flag.Parse()
 
 	_ = godotenv.Load()
@@ -57,7 +58,7 @@ func main() {
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 	log.Logger = logger
 
-	wl, err := watchlist.Load(wlPath)
+	wl, err := watchlist.Load(wlPath, cfg.Massive.Market)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to load watchlist")
 	}
@@ -66,9 +67,10 @@ func main() {
 		log.Fatal().Msg("watchlist has zero symbols; add symbols to watchlist.yaml")
 	}
 
-	// Secrets from env
+	// Secrets from env (Massive key is not needed when replaying a file or simulating)
+	simulated := strings.EqualFold(strings.TrimSpace(cfg.Massive.Feed), "simulated")
 	massiveKey := strings.TrimSpace(os.Getenv(cfg.Massive.APIKeyEnv))
-	if massiveKey == "" {
+	if massiveKey == "" && replayPath == "" && !simulated {
 		log.Fatal().Str("env", cfg.Massive.APIKeyEnv).Msg("missing Massive API key env var")
 	}
 	openAIKey := strings.TrimSpace(os.Getenv(cfg.OpenAI.APIKeyEnv))
@@ -170,12 +172,117 @@ func main() {
 		}
 	}()
 
+	// Market source: live Massive feed, simulator, or a recorded tick file under -replay.
+	// Clock used for cloud snapshots: wall time live, recorded time under replay.
+	var src feed.MarketSource
+	now := time.Now
+	if replayPath != "" {
+		player := replay.NewPlayer(replay.Config{
+			Path:   replayPath,
+			Speed:  replaySpeed,
+			MaxGap: replayMaxGap,
+		}, log.Logger)
+		src = player
+		now = player.Now
+	} else if simulated {
+		src = feed.NewSimulator(simConfig(cfg.Simulator), log.Logger)
+	} else {
+		// One websocket per market (a Massive connection serves a single market), each kept
+		// alive by a supervisor: a dropped feed is re-dialed instead of ending the process.
+		markets := wl.Markets()
+		onStatus := feedStatusHandler(ctx, cfg.Massive.Reconnect.Speak, len(markets) > 1, ttsClient, srv)
+
+		var srcs []feed.MarketSource
+		for _, market := range markets {
+			mcfg := feed.MassiveConfig{
+				APIKey: massiveKey,
+				Feed:   cfg.Massive.Feed,
+				Market: market,
+				Trades: cfg.Cloud.Enabled,
+
+				TradeTickers: wl.TradeTickers(),
+				QuoteTickers: wl.QuoteTickers(),
+			}
+			// Fail fast on bad config; connection problems are retried.
+			if err := mcfg.Validate(); err != nil {
+				log.Fatal().Err(err).Str("market", market).Msg("invalid Massive websocket config")
+			}
+			srcs = append(srcs, feed.NewSupervisor(feed.SupervisorConfig{
+				Name:       market,
+				MinBackoff: cfg.Massive.Reconnect.MinBackoff.ToDuration(),
+				MaxBackoff: cfg.Massive.Reconnect.MaxBackoff.ToDuration(),
+				OnStatus:   onStatus,
+			}, func() (feed.MarketSource, error) {
+				return feed.NewMassive(mcfg, log.Logger)
+			}, log.Logger))
+		}
+		src = srcs[0]
+		if len(srcs) > 1 {
+			src = feed.Merge(srcs...)
+		}
+	}
+
+	// Session calendar (pre / regular / after / closed; holidays from file)
+	cal := sessionCalendar(cfg.Session)
+
 	// Radar engine (per-symbol alerts)
 	engine := radar.NewEngine(radar.Config{
 		GlobalCooldown: cfg.Radar.GlobalCooldown.ToDuration(),
 		HistoryWindow:  cfg.Radar.HistoryWindow.ToDuration(),
+		BarRetention:   cfg.Radar.BarRetention,
+		Calendar:       cal,
+		ResetOn:        phases(cfg.Session.ResetOn),
 	}, wl, log.Logger)
 
+	// Engine state (baselines, edge flags, cooldowns): restored if saved in the current session,
+	// saved periodically and on shutdown. Replays and the simulator do not continue a live session.
+	saveState := func() {}
+	if cfg.State.Enabled && replayPath == "" && !simulated {
+		n, err := engine.LoadState(cfg.State.Path, time.Now())
+		if err != nil {
+			log.Warn().Err(err).Str("file", cfg.State.Path).Msg("cannot restore engine state; starting fresh")
+		} else if n > 0 {
+			log.Info().Int("symbols", n).Str("file", cfg.State.Path).Msg("engine state restored")
+		}
+		saveState = func() {
+			if err := engine.SaveState(cfg.State.Path, time.Now()); err != nil {
+				log.Warn().Err(err).Str("file", cfg.State.Path).Msg("failed to save engine state")
+			}
+		}
+		go func() {
+			tk := time.NewTicker(cfg.State.SaveEvery.ToDuration())
+			defer tk.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tk.C:
+					saveState()
+				}
+			}
+		}()
+	}
+
+	// Previous closes for base_change reference: prev_close (reloaded when pre-market opens).
+	// REST would return today's previous close, so it is skipped for replays and the simulator.
+	loadPrevCloses := func() {}
+	if pcTickers := wl.PrevCloseTickers(); len(pcTickers) > 0 {
+		useREST := cfg.Reference.REST && replayPath == "" && !simulated
+		loadPrevCloses = func() {
+			go refreshPrevCloses(ctx, engine, pcTickers, cfg.Reference, useREST, massiveKey)
+		}
+		loadPrevCloses()
+	}
+	// Average daily volumes for volume_spike baseline: adv (reloaded with the previous closes).
+	loadAvgVolumes := func() {}
+	if avTickers := wl.AvgVolumeTickers(); len(avTickers) > 0 {
+		loadAvgVolumes = func() {
+			loadAvgVolumeFile(engine, avTickers, cfg.Reference.AvgVolumeFile)
+		}
+		loadAvgVolumes()
+	}
+
 	// Cloud engine (watchlist-wide “geiger” signal)
 	cloud := radar.NewCloudEngine(radar.CloudConfig{
 		Enabled:       cfg.Cloud.Enabled,
@@ -205,7 +312,7 @@ func main() {
 				case <-ctx.Done():
 					return
 				case <-tk.C:
-					snap := cloud.Snapshot(time.Now())
+					snap := cloud.Snapshot(now())
 					srv.Broadcast(server.Event{
 						Time:      snap.Time,
 						Symbol:    "CLOUD",
@@ -228,6 +335,52 @@ func main() {
 	}
 
 	alertCh := make(chan radar.Alert, 1024)
+	// emit queues an alert for the workers. Live, a full queue drops it rather than stall
+	// the feed; a replay waits, so it shows every alert the rules produce.
+	emit := func(a radar.Alert, what string) {
+		if replayPath != "" {
+			select {
+			case alertCh <- a:
+			case <-ctx.Done():
+			}
+			return
+		}
+		select {
+		case alertCh <- a:
+		default:
+			log.Warn().Msg("alert channel full; dropping " + what)
+		}
+	}
+
+	// Health watchdog (not under replay: the recorded feed ends, and its gaps are history)
+	var watchdog *radar.Watchdog
+	if cfg.Watchdog.Enabled && replayPath == "" {
+		watchdog = radar.NewWatchdog(radar.WatchdogConfig{
+			SymbolStale: cfg.Watchdog.SymbolStale.ToDuration(),
+			FeedSilent:  cfg.Watchdog.FeedSilent.ToDuration(),
+			Calendar:    cal,
+			Sessions:    phases(cfg.Watchdog.Sessions),
+		}, wl, time.Now, log.Logger)
+		go func() {
+			tk := time.NewTicker(cfg.Watchdog.CheckEvery.ToDuration())
+			defer tk.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tk.C:
+					for _, a := range watchdog.Check() {
+						select {
+						case alertCh <- a:
+						default:
+							log.Warn().Msg("alert channel full; dropping health alert")
+						}
+					}
+				}
+			}
+		}()
+	}
 
 	// Alert workers: generate / cache audio then broadcast to UI
 	for i := 0; i < cfg.Radar.AlertWorkers; i++ {
@@ -237,8 +390,12 @@ func main() {
 				case <-ctx.Done():
 					return
 				case a := <-alertCh:
+					evTime := a.Time
+					if evTime.IsZero() {
+						evTime = time.Now()
+					}
 					ev := server.Event{
-						Time:      time.Now(),
+						Time:      evTime,
 						Symbol:    a.Symbol,
 						Price:     a.Price,
 						Type:      string(a.Type),
@@ -265,313 +422,359 @@ func main() {
 		}(i)
 	}
 
-	// Massive WS client
-	feedConst := parseMassiveFeed(cfg.Massive.Feed)
-	marketConst := parseMassiveMarket(cfg.Massive.Market)
-
-	ws, err := massivews.New(massivews.Config{
-		APIKey: massiveKey,
-		Feed:   feedConst,
-		Market: marketConst,
-	})
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to create Massive websocket client")
+	// Raw feed recorder (nil when disabled; nil recorder is a no-op).
+	// Replays are never re-recorded, and simulated ticks never land next to real ones.
+	var rec *recorder.Recorder
+	if cfg.Recorder.Enabled && replayPath == "" && !simulated {
+		rec, err = recorder.New(recorder.Config{
+			Dir:      cfg.Recorder.Dir,
+			MaxBytes: int64(cfg.Recorder.MaxSizeMB) * 1024 * 1024,
+			Gzip:     cfg.Recorder.Gzip,
+			Location: cal.Location(),
+		}, log.Logger)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to init feed recorder")
+		}
+		defer rec.Close()
+		log.Info().Str("dir", cfg.Recorder.Dir).Msg("recording raw feed")
 	}
-	defer ws.Close()
 
-	if err := ws.Connect(); err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to Massive websocket")
+	if err := src.Start(ctx); err != nil {
+		log.Fatal().Err(err).Msg("failed to start market source")
+	}
+	if err := src.Subscribe(tickers...); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to watchlist tickers")
 	}
 
-	// Subscribe to 1-second aggregates for watchlist tickers
-	if err := ws.Subscribe(massivews.StocksSecAggs, tickers...); err != nil {
-		log.Fatal().Err(err).Msg("failed to subscribe to Massive topic stocks sec aggs")
+	if simulated && replayPath == "" {
+		log.Warn().Msg("massive.feed is \"simulated\": prices are SYNTHETIC, not market data")
+	}
+	if replayPath != "" {
+		log.Info().
+			Str("file", replayPath).
+			Float64("speed", replaySpeed).
+			Str("addr", srv.Addr()).
+			Msg("replaying. Open the UI in your browser and click Enable Audio")
+	} else {
+		log.Info().
+			Int("symbols", len(tickers)).
+			Str("addr", srv.Addr()).
+			Msg("running. Open the UI in your browser and click Enable Audio")
 	}
 
-	// Optional but recommended for “random timing” pulses:
-	// trades arrive at irregular times, unlike fixed 1s aggregates.
-	// If your account/topic permissions don’t allow it, we just log and continue.
-	if cfg.Cloud.Enabled {
-		if err := ws.Subscribe(massivews.StocksTrades, tickers...); err != nil {
-			log.Warn().Err(err).Msg("could not subscribe to stocks trades; cloud pulses will be less granular")
-		} else {
-			log.Info().Msg("subscribed to stocks trades for event-driven cloud pulses")
+	// Fan-out: every tick goes to the recorder and cloud; aggregates also drive the alert engine,
+	// quotes drive the quote rules and trades the large print rule.
+	// Trades never feed the price rules (that would make them far more sensitive).
+	dispatch := func(t feed.Tick) {
+		rec.Write(t)
+		watchdog.Seen(t.Symbol)
+
+		// NBBO updates are too frequent for the cloud, except forex where quotes are the only pulses.
+		pulses := t.Kind != feed.KindQuote || watchlist.MarketOf(t.Symbol) == watchlist.MarketForex
+
+		// Update cloud + emit event-driven pulse (sound is tied to real ticks)
+		if cfg.Cloud.Enabled && pulses {
+			pulse, ok := cloud.Update(t.Symbol, t.Price, t.Volume, t.Time)
+			// Only emit if there was actual activity/movement:
+			if ok && (pulse.DeltaPct != 0 || t.Volume > 0) {
+				srv.Broadcast(server.Event{
+					Time:      pulse.Time,
+					Symbol:    pulse.Symbol,
+					Price:     pulse.Price,
+					Volume:    pulse.Volume,
+					Type:      "cloud_pulse",
+					Message:   "",
+					Direction: pulse.Direction,
+					Strength:  pulse.Strength,
+					DeltaPct:  pulse.DeltaPct,
+				})
+			}
+		}
+
+		// Per-symbol alert engine
+		var alerts []radar.Alert
+		switch t.Kind {
+		case feed.KindAgg:
+			alerts = engine.UpdateAgg(t.Symbol, radar.Agg{
+				Price:   t.Price,
+				Volume:  t.Volume,
+				VWAP:    t.VWAP,
+				DayVWAP: t.DayVWAP,
+				High:    t.High,
+				Low:     t.Low,
+				DayOpen: t.DayOpen,
+				Time:    t.Time,
+			})
+		case feed.KindQuote:
+			alerts = engine.UpdateQuote(t.Symbol, t.Bid, t.Ask, t.BidSize, t.AskSize, t.Time)
+		case feed.KindTrade:
+			alerts = engine.UpdateTrade(t.Symbol, t.Price, t.Volume, t.Exchange, t.Conditions, t.Time)
+		default:
+			return
+		}
+		for _, a := range alerts {
+			emit(a, "alert")
 		}
 	}
 
-	log.Info().
-		Int("symbols", len(tickers)).
-		Str("addr", srv.Addr()).
-		Msg("running. Open the UI in your browser and click Enable Audio")
+	// Session transitions: by the wall clock live, by tick time under replay.
+	tracker := session.NewTracker(cal)
+	announce := func(now time.Time) {
+		phase, changed := tracker.Update(now)
+		if !changed {
+			return
+		}
+		log.Info().Str("session", string(phase)).Msg("session changed")
+		if phase == session.Pre {
+			loadPrevCloses()
+			loadAvgVolumes()
+		}
+		if !cfg.Session.Announce {
+			return
+		}
+		emit(radar.Alert{
+			Type:      radar.AlertSession,
+			Symbol:    "SESSION",
+			Time:      now,
+			Message:   "session: " + string(phase),
+			SpeakText: phase.Spoken(),
+		}, "session announcement")
+	}
+	sessionTick := time.NewTicker(time.Second)
+	defer sessionTick.Stop()
 
 	// Read stream
+	ticks := src.Ticks()
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info().Msg("shutting down")
+			saveState()
 			return
 
-		case err := <-ws.Error():
-			// Fatal errors (auth, etc.)
-			log.Error().Err(err).Msg("Massive websocket fatal error")
+		case now := <-sessionTick.C:
+			if replayPath == "" {
+				announce(now)
+			}
+
+		case err := <-src.Errors():
+			// Fatal errors (unreadable replay file, etc.); live feed failures are retried by the supervisor
+			log.Error().Err(err).Msg("market source fatal error")
 			cancel()
 
-		case out, more := <-ws.Output():
+		case t, more := <-ticks:
 			if !more {
-				log.Warn().Msg("Massive websocket output channel closed")
+				if replayPath != "" {
+					// Keep the UI up so the replayed alerts can still be reviewed.
+					ticks = nil
+					continue
+				}
+				log.Warn().Msg("market source closed")
 				cancel()
 				continue
 			}
+			if replayPath != "" {
+				announce(t.Time)
+			}
+			dispatch(t)
+		}
+	}
+}
 
-			switch msg := out.(type) {
-			case wsmodels.EquityAgg:
-				t, ok := tickFromAny(msg)
-				if !ok {
-					continue
-				}
-
-				// Update cloud + emit event-driven pulse (sound is now tied to real ticks)
-				if cfg.Cloud.Enabled {
-					pulse, ok2 := cloud.Update(t.Symbol, t.Price, t.Volume, t.Time)
-					// Only emit if there was actual activity/movement:
-					if ok2 && (pulse.DeltaPct != 0 || t.Volume > 0) {
-						srv.Broadcast(server.Event{
-							Time:      pulse.Time,
-							Symbol:    pulse.Symbol,
-							Price:     pulse.Price,
-							Volume:    pulse.Volume,
-							Type:      "cloud_pulse",
-							Message:   "",
-							Direction: pulse.Direction,
-							Strength:  pulse.Strength,
-							DeltaPct:  pulse.DeltaPct,
-						})
-					}
-				}
-
-				// Per-symbol alert engine
-				alerts := engine.Update(t.Symbol, t.Price, t.Volume, t.Time)
-				for _, a := range alerts {
-					select {
-					case alertCh <- a:
-					default:
-						log.Warn().Msg("alert channel full; dropping alert")
-					}
-				}
-
-			case *wsmodels.EquityAgg:
-				t, ok := tickFromAny(msg)
-				if !ok {
-					continue
-				}
-
-				if cfg.Cloud.Enabled {
-					pulse, ok2 := cloud.Update(t.Symbol, t.Price, t.Volume, t.Time)
-					if ok2 && (pulse.DeltaPct != 0 || t.Volume > 0) {
-						srv.Broadcast(server.Event{
-							Time:      pulse.Time,
-							Symbol:    pulse.Symbol,
-							Price:     pulse.Price,
-							Volume:    pulse.Volume,
-							Type:      "cloud_pulse",
-							Message:   "",
-							Direction: pulse.Direction,
-							Strength:  pulse.Strength,
-							DeltaPct:  pulse.DeltaPct,
-						})
-					}
-				}
+// feedStatusHandler broadcasts supervisor state changes as "feed_status" events and,
+// if speak is set, voices the connected -> reconnecting and reconnecting -> connected edges.
+func feedStatusHandler(ctx context.Context, speak bool, multi bool, ttsClient *tts.Client, srv *server.Server) func(feed.Status) {
+	return func(st feed.Status) {
+		if st.State == feed.StateStopped {
+			return
+		}
 
-				alerts := engine.Update(t.Symbol, t.Price, t.Volume, t.Time)
-				for _, a := range alerts {
-					select {
-					case alertCh <- a:
-					default:
-						log.Warn().Msg("alert channel full; dropping alert")
-					}
-				}
+		name := "feed"
+		if multi {
+			name = st.Name + " feed"
+		}
+		msg := name + " " + string(st.State)
+		if st.State == feed.StateReconnecting {
+			msg += fmt.Sprintf(" (attempt %d, retry in %s)", st.Attempt, st.RetryIn.Round(100*time.Millisecond))
+			if st.Err != nil {
+				msg += ": " + st.Err.Error()
+			}
+		}
 
-			// Trades: use for cloud pulses (irregular timing), but do NOT feed per-symbol alert engine
-			// unless you intentionally want much higher alert sensitivity.
-			case wsmodels.EquityTrade:
-				t, ok := tickFromAny(msg)
-				if !ok {
-					continue
-				}
-				if cfg.Cloud.Enabled {
-					pulse, ok2 := cloud.Update(t.Symbol, t.Price, t.Volume, t.Time)
-					if ok2 && (pulse.DeltaPct != 0 || t.Volume > 0) {
-						srv.Broadcast(server.Event{
-							Time:      pulse.Time,
-							Symbol:    pulse.Symbol,
-							Price:     pulse.Price,
-							Volume:    pulse.Volume,
-							Type:      "cloud_pulse",
-							Message:   "",
-							Direction: pulse.Direction,
-							Strength:  pulse.Strength,
-							DeltaPct:  pulse.DeltaPct,
-						})
-					}
-				}
+		ev := server.Event{
+			Time:    st.Time,
+			Symbol:  "FEED",
+			Type:    "feed_status",
+			Message: msg,
+			State:   string(st.State),
+			Attempt: st.Attempt,
+		}
 
-			case *wsmodels.EquityTrade:
-				t, ok := tickFromAny(msg)
-				if !ok {
-					continue
-				}
-				if cfg.Cloud.Enabled {
-					pulse, ok2 := cloud.Update(t.Symbol, t.Price, t.Volume, t.Time)
-					if ok2 && (pulse.DeltaPct != 0 || t.Volume > 0) {
-						srv.Broadcast(server.Event{
-							Time:      pulse.Time,
-							Symbol:    pulse.Symbol,
-							Price:     pulse.Price,
-							Volume:    pulse.Volume,
-							Type:      "cloud_pulse",
-							Message:   "",
-							Direction: pulse.Direction,
-							Strength:  pulse.Strength,
-							DeltaPct:  pulse.DeltaPct,
-						})
-					}
-				}
+		var text string
+		switch {
+		case st.State == feed.StateReconnecting && st.Prev == feed.StateConnected:
+			ev.Direction = "down"
+			text = strings.ToUpper(name[:1]) + name[1:] + " lost."
+		case st.State == feed.StateConnected && st.Prev == feed.StateReconnecting:
+			ev.Direction = "up"
+			text = strings.ToUpper(name[:1]) + name[1:] + " restored."
+		}
 
-			default:
-				// ignore other message types
-			}
+		if !speak || text == "" {
+			srv.Broadcast(ev)
+			return
 		}
+		go func() {
+			res, err := ttsClient.SpeakToFile(ctx, text)
+			if err != nil {
+				log.Error().Err(err).Str("text", text).Msg("tts failed; broadcasting feed status without audio")
+			} else {
+				ev.AudioURL = "/audio/" + filepath.Base(res.Path)
+				ev.CacheHit = res.CacheHit
+			}
+			srv.Broadcast(ev)
+		}()
 	}
 }
 
-func directionFromAlertType(t radar.AlertType) string {
-	s := strings.ToLower(strings.TrimSpace(string(t)))
-	switch {
-	case strings.Contains(s, "down") || strings.Contains(s, "below"):
-		return "down"
-	case strings.Contains(s, "up") || strings.Contains(s, "above"):
-		return "up"
-	default:
-		return ""
+// refreshPrevCloses loads previous closes (file first, then Massive REST) into the engine.
+func refreshPrevCloses(ctx context.Context, engine *radar.Engine, tickers []string, c config.ReferenceConfig, rest bool, apiKey string) {
+	var chain refdata.Chain
+	if c.PrevCloseFile != "" {
+		f, err := refdata.LoadFile(c.PrevCloseFile)
+		switch {
+		case err == nil:
+			chain = append(chain, f)
+		case os.IsNotExist(err) && rest:
+			log.Debug().Str("file", c.PrevCloseFile).Msg("no previous-close file; using REST")
+		default:
+			log.Warn().Err(err).Str("file", c.PrevCloseFile).Msg("cannot read previous-close file")
+		}
 	}
-}
-
-func parseMassiveFeed(s string) massivews.Feed {
-	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "", "realtime", "real_time", "real-time":
-		return massivews.RealTime
-	case "delayed":
-		return massivews.Delayed
-	default:
-		return massivews.RealTime
+	if rest {
+		chain = append(chain, refdata.NewMassiveREST(c.RESTURL, apiKey, c.Timeout.ToDuration()))
+	}
+	if len(chain) == 0 {
+		log.Warn().Msg("base_change reference prev_close has no source; set reference.prev_close_file")
+		return
 	}
-}
 
-func parseMassiveMarket(s string) massivews.Market {
-	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "", "stocks", "equities":
-		return massivews.Stocks
-	case "crypto":
-		return massivews.Crypto
-	case "forex":
-		return massivews.Forex
-	case "options":
-		return massivews.Options
-	default:
-		return massivews.Stocks
+	loaded := 0
+	for _, t := range tickers {
+		p, err := chain.PrevClose(ctx, t)
+		if err != nil {
+			log.Warn().Err(err).Str("symbol", t).Msg("no previous close; base_change vs prev close inactive")
+			continue
+		}
+		engine.SetPrevClose(t, p)
+		loaded++
 	}
+	log.Info().Int("loaded", loaded).Int("tickers", len(tickers)).Msg("previous closes loaded")
 }
 
-// tickFromAny intentionally avoids relying on specific struct fields.
-// It marshals to JSON then pulls common keys (sym/ticker, close/c, volume/v, timestamp/t/e).
-func tickFromAny(v any) (Tick, bool) {
-	b, err := json.Marshal(v)
+// loadAvgVolumeFile loads average daily volumes (ticker: shares) into the engine.
+func loadAvgVolumeFile(engine *radar.Engine, tickers []string, path string) {
+	if path == "" {
+		log.Warn().Msg("volume_spike baseline adv has no source; set reference.avg_volume_file")
+		return
+	}
+	f, err := refdata.LoadFile(path)
 	if err != nil {
-		return Tick{}, false
+		log.Warn().Err(err).Str("file", path).Msg("cannot read average volume file; volume_spike vs adv inactive")
+		return
 	}
-	var m map[string]any
-	if err := json.Unmarshal(b, &m); err != nil {
-		return Tick{}, false
+	loaded := 0
+	for _, t := range tickers {
+		v, ok := f.Lookup(t)
+		if !ok {
+			log.Warn().Str("symbol", t).Str("file", path).Msg("no average volume; volume_spike vs adv inactive")
+			continue
+		}
+		engine.SetAvgVolume(t, v)
+		loaded++
 	}
+	log.Info().Int("loaded", loaded).Int("tickers", len(tickers)).Msg("average volumes loaded")
+}
 
-	sym := pickString(m, "sym", "Sym", "symbol", "Symbol", "ticker", "Ticker", "T")
-	price := pickFloat(m, "c", "C", "close", "Close", "price", "Price", "p", "P")
-	// volume/size varies by message type (aggs vs trades)
-	vol := pickFloat(m,
-		"v", "V", "volume", "Volume",
-		"size", "Size", "qty", "Qty", "shares", "Shares",
-		"s", "S", "q", "Q",
-	)
-
-	// timestamps often in ms
-	tsms := pickInt64(m, "e", "E", "end", "End", "t", "T", "timestamp", "Timestamp")
-	ts := time.Now()
-	if tsms > 0 {
-		// if it's seconds (10 digits) convert; if ms (13 digits) use milli
-		if tsms < 1_000_000_000_000 {
-			ts = time.Unix(tsms, 0)
-		} else {
-			ts = time.UnixMilli(tsms)
+func sessionCalendar(c config.SessionConfig) *session.Calendar {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		log.Warn().Err(err).Str("timezone", c.Timezone).Msg("session: unknown timezone; using America/New_York")
+		loc = nil
+	}
+	var holidays []session.Holiday
+	if c.HolidaysFile != "" {
+		holidays, err = session.LoadHolidays(c.HolidaysFile)
+		if err != nil {
+			log.Warn().Err(err).Str("file", c.HolidaysFile).Msg("session: no holiday calendar; holidays will look like normal sessions")
 		}
 	}
-
-	sym = strings.ToUpper(strings.TrimSpace(sym))
-	if sym == "" || price <= 0 {
-		return Tick{}, false
+	clock := func(s string) time.Duration {
+		d, _ := session.ClockTime(s) // invalid -> 0 -> session default
+		return d
 	}
-	return Tick{Symbol: sym, Price: price, Volume: vol, Time: ts}, true
+	return session.New(session.Config{
+		Location:   loc,
+		PreOpen:    clock(c.PreOpen),
+		Open:       clock(c.Open),
+		Close:      clock(c.Close),
+		AfterClose: clock(c.AfterClose),
+		Holidays:   holidays,
+	})
 }
 
-func pickString(m map[string]any, keys ...string) string {
-	for _, k := range keys {
-		if v, ok := m[k]; ok {
-			switch vv := v.(type) {
-			case string:
-				return vv
-			}
+// phases parses session names from config, skipping unknown ones.
+func phases(names []string) []session.Phase {
+	out := make([]session.Phase, 0, len(names))
+	for _, n := range names {
+		p, ok := session.ParsePhase(n)
+		if !ok {
+			log.Warn().Str("session", n).Msg("unknown session name; ignored")
+			continue
 		}
+		out = append(out, p)
 	}
-	return ""
+	return out
 }
 
-func pickFloat(m map[string]any, keys ...string) float64 {
-	for _, k := range keys {
-		if v, ok := m[k]; ok {
-			switch vv := v.(type) {
-			case float64:
-				return vv
-			case float32:
-				return float64(vv)
-			case int:
-				return float64(vv)
-			case int64:
-				return float64(vv)
-			case json.Number:
-				f, _ := vv.Float64()
-				return f
-			}
-		}
+func simConfig(c config.SimulatorConfig) feed.SimConfig {
+	events := make([]feed.SimEvent, 0, len(c.Events))
+	for _, ev := range c.Events {
+		events = append(events, feed.SimEvent{
+			At:      ev.At.ToDuration(),
+			Symbol:  strings.ToUpper(strings.TrimSpace(ev.Ticker)),
+			MovePct: ev.MovePct,
+			Over:    ev.Over.ToDuration(),
+		})
+	}
+	prices := make(map[string]float64, len(c.Prices))
+	for k, v := range c.Prices {
+		prices[strings.ToUpper(strings.TrimSpace(k))] = v
+	}
+	return feed.SimConfig{
+		AggEvery:      c.AggEvery.ToDuration(),
+		DriftPct:      c.DriftPct,
+		VolatilityPct: c.VolatilityPct,
+		Correlation:   c.Correlation,
+		TradesPerSec:  c.TradesPerSec,
+		MeanTradeSize: c.MeanTradeSize,
+		StartPrice:    c.StartPrice,
+		Prices:        prices,
+		Seed:          c.Seed,
+		Events:        events,
 	}
-	return 0
 }
 
-func pickInt64(m map[string]any, keys ...string) int64 {
-	for _, k := range keys {
-		if v, ok := m[k]; ok {
-			switch vv := v.(type) {
-			case int64:
-				return vv
-			case int:
-				return int64(vv)
-			case float64:
-				return int64(vv)
-			case json.Number:
-				i, _ := vv.Int64()
-				return i
-			}
-		}
+func directionFromAlertType(t radar.AlertType) string {
+	s := strings.ToLower(strings.TrimSpace(string(t)))
+	switch {
+	case strings.Contains(s, "down") || strings.Contains(s, "below") || strings.HasSuffix(s, "_low"):
+		return "down"
+	case strings.Contains(s, "up") || strings.Contains(s, "above") || strings.HasSuffix(s, "_high"):
+		return "up"
+	default:
+		return ""
 	}
-	return 0
 }
 
 func absInt(x int) int {
@@ -57,12 +57,18 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 }
 
 type Config struct {
-	Server ServerConfig `yaml:"server"`
-	Massive MassiveConfig `yaml:"massive"`
-	OpenAI OpenAIConfig `yaml:"openai"`
-	Cache  CacheConfig  `yaml:"cache"`
-	Radar  RadarConfig  `yaml:"radar"`
-	Cloud  CloudConfig  `yaml:"cloud"`
+	Server    ServerConfig    `yaml:"server"`
+	Massive   MassiveConfig   `yaml:"massive"`
+	OpenAI    OpenAIConfig    `yaml:"openai"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Radar     RadarConfig     `yaml:"radar"`
+	Cloud     CloudConfig     `yaml:"cloud"`
+	Recorder  RecorderConfig  `yaml:"recorder"`
+	Simulator SimulatorConfig `yaml:"simulator"`
+	Watchdog  WatchdogConfig  `yaml:"watchdog"`
+	Session   SessionConfig   `yaml:"session"`
+	Reference ReferenceConfig `yaml:"reference"`
+	State     StateConfig     `yaml:"state"`
 }
 
 type ServerConfig struct {
@@ -73,8 +79,17 @@ type ServerConfig struct {
 
 type MassiveConfig struct {
 	APIKeyEnv string `yaml:"api_key_env"`
-	Feed      string `yaml:"feed"`   // realtime, delayed
-	Market    string `yaml:"market"` // stocks, crypto, forex, options
+	Feed      string `yaml:"feed"`   // realtime, delayed, simulated
+	Market    string `yaml:"market"` // default watchlist market: stocks, crypto, forex, options
+
+	Reconnect ReconnectConfig `yaml:"reconnect"`
+}
+
+// ReconnectConfig controls the feed supervisor that re-dials the websocket after a failure.
+type ReconnectConfig struct {
+	MinBackoff Duration `yaml:"min_backoff"` // first retry delay; doubles per failed attempt
+	MaxBackoff Duration `yaml:"max_backoff"`
+	Speak      bool     `yaml:"speak"` // say "feed lost" / "feed restored"
 }
 
 type OpenAIConfig struct {
@@ -97,6 +112,9 @@ type RadarConfig struct {
 	GlobalCooldown Duration `yaml:"global_cooldown"`
 	HistoryWindow  Duration `yaml:"history_window"`
 	AlertWorkers   int      `yaml:"alert_workers"`
+
+	// Closed bars kept per symbol and bar size for moving averages and volatility (independent of history_window).
+	BarRetention int `yaml:"bar_retention"`
 }
 
 type CloudConfig struct {
@@ -131,6 +149,77 @@ type CloudConfig struct {
 	NetBucketFlat int `yaml:"net_bucket_flat"`
 }
 
+// RecorderConfig controls the raw feed recorder (JSONL tick files, replayable with -replay).
+type RecorderConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Dir       string `yaml:"dir"`
+	MaxSizeMB int    `yaml:"max_size_mb"` // rotate after N MB (uncompressed); files also rotate daily
+	Gzip      bool   `yaml:"gzip"`
+}
+
+// WatchdogConfig raises health alerts when symbols or a whole feed go quiet during market hours.
+type WatchdogConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	SymbolStale Duration `yaml:"symbol_stale"` // "no data for MU in 2 minutes"
+	FeedSilent  Duration `yaml:"feed_silent"`  // "market feed silent"
+	CheckEvery  Duration `yaml:"check_every"`
+	Sessions    []string `yaml:"sessions"` // phases in which stocks/options must deliver data
+}
+
+// SessionConfig is the US equity session calendar (pre-market, regular, after-hours, closed).
+type SessionConfig struct {
+	Timezone     string   `yaml:"timezone"`
+	PreOpen      string   `yaml:"pre_open"`    // "04:00"
+	Open         string   `yaml:"open"`        // "09:30"
+	Close        string   `yaml:"close"`       // "16:00"
+	AfterClose   string   `yaml:"after_close"` // "20:00"
+	HolidaysFile string   `yaml:"holidays_file"`
+	ResetOn      []string `yaml:"reset_on"` // base_change baselines reset when these phases begin
+	Announce     bool     `yaml:"announce"` // speak "regular session open" etc.
+}
+
+// ReferenceConfig supplies previous closes for base_change reference: prev_close
+// (the file wins; REST fills in tickers the file does not list, live feed only)
+// and average daily volumes for volume_spike baseline: adv.
+type ReferenceConfig struct {
+	PrevCloseFile string   `yaml:"prev_close_file"` // YAML map ticker: price
+	REST          bool     `yaml:"rest"`            // fetch missing ones from Massive REST
+	RESTURL       string   `yaml:"rest_url"`        // point at a local stub for testing
+	Timeout       Duration `yaml:"timeout"`
+
+	AvgVolumeFile string `yaml:"avg_volume_file"` // YAML map ticker: shares/day (volume_spike baseline: adv)
+}
+
+// StateConfig persists alert engine state (baselines, cooldowns) across restarts.
+// A snapshot is only restored when it was taken in the current session.
+type StateConfig struct {
+	Enabled   bool     `yaml:"enabled"`
+	Path      string   `yaml:"path"`
+	SaveEvery Duration `yaml:"save_every"` // also saved on shutdown
+}
+
+// SimulatorConfig drives the synthetic market used when massive.feed is "simulated".
+type SimulatorConfig struct {
+	AggEvery      Duration           `yaml:"agg_every"`
+	DriftPct      float64            `yaml:"drift_pct"`      // % per hour
+	VolatilityPct float64            `yaml:"volatility_pct"` // stdev, % per minute
+	Correlation   float64            `yaml:"correlation"`    // 0..1
+	TradesPerSec  float64            `yaml:"trades_per_sec"`
+	MeanTradeSize float64            `yaml:"mean_trade_size"`
+	StartPrice    float64            `yaml:"start_price"`
+	Prices        map[string]float64 `yaml:"prices"`
+	Seed          int64              `yaml:"seed"`
+	Events        []SimEventConfig   `yaml:"events"`
+}
+
+// SimEventConfig is a scripted move, e.g. { at: "30s", ticker: MU, move_pct: -2, over: "5s" }.
+type SimEventConfig struct {
+	At      Duration `yaml:"at"`
+	Ticker  string   `yaml:"ticker"` // empty or "*" = all symbols
+	MovePct float64  `yaml:"move_pct"`
+	Over    Duration `yaml:"over"`
+}
+
 func Default() Config {
 	return Config{
 		Server: ServerConfig{
@@ -142,6 +231,11 @@ func Default() Config {
 			APIKeyEnv: "MASSIVE_API_KEY",
 			Feed:      "realtime",
 			Market:    "stocks",
+			Reconnect: ReconnectConfig{
+				MinBackoff: Duration(time.Second),
+				MaxBackoff: Duration(60 * time.Second),
+				Speak:      true,
+			},
 		},
 		OpenAI: OpenAIConfig{
 			APIKeyEnv:      "OPENAI_API_KEY",
@@ -161,6 +255,7 @@ func Default() Config {
 			GlobalCooldown: Duration(25 * time.Second),
 			HistoryWindow:  Duration(5 * time.Minute),
 			AlertWorkers:   2,
+			BarRetention:   500,
 		},
 		Cloud: CloudConfig{
 			Enabled:       true,
@@ -176,6 +271,50 @@ func Default() Config {
 			NetBucketStep: 20,
 			NetBucketFlat: 20,
 		},
+		Simulator: SimulatorConfig{
+			AggEvery:      Duration(time.Second),
+			DriftPct:      0,
+			VolatilityPct: 0.08,
+			Correlation:   0.5,
+			TradesPerSec:  4,
+			MeanTradeSize: 100,
+			StartPrice:    100,
+		},
+		Recorder: RecorderConfig{
+			Enabled:   false,
+			Dir:       "./data/ticks",
+			MaxSizeMB: 256,
+			Gzip:      true,
+		},
+		Watchdog: WatchdogConfig{
+			Enabled:     true,
+			SymbolStale: Duration(2 * time.Minute),
+			FeedSilent:  Duration(30 * time.Second),
+			CheckEvery:  Duration(5 * time.Second),
+			Sessions:    []string{"regular"},
+		},
+		Reference: ReferenceConfig{
+			PrevCloseFile: "prev_close.yaml",
+			REST:          true,
+			RESTURL:       "https://api.massive.com",
+			Timeout:       Duration(10 * time.Second),
+			AvgVolumeFile: "avg_volume.yaml",
+		},
+		Session: SessionConfig{
+			Timezone:     "America/New_York",
+			PreOpen:      "04:00",
+			Open:         "09:30",
+			Close:        "16:00",
+			AfterClose:   "20:00",
+			HolidaysFile: "holidays.yaml",
+			ResetOn:      []string{"pre", "regular"},
+			Announce:     true,
+		},
+		State: StateConfig{
+			Enabled:   true,
+			Path:      "./data/radar-state.json",
+			SaveEvery: Duration(30 * time.Second),
+		},
 	}
 }
 
@@ -202,6 +341,12 @@ func Load(path string) (Config, error) {
 	if cfg.Massive.APIKeyEnv == "" {
 		cfg.Massive.APIKeyEnv = "MASSIVE_API_KEY"
 	}
+	if cfg.Massive.Reconnect.MinBackoff.ToDuration() <= 0 {
+		cfg.Massive.Reconnect.MinBackoff = Duration(time.Second)
+	}
+	if cfg.Massive.Reconnect.MaxBackoff.ToDuration() < cfg.Massive.Reconnect.MinBackoff.ToDuration() {
+		cfg.Massive.Reconnect.MaxBackoff = Duration(60 * time.Second)
+	}
 	if cfg.OpenAI.APIKeyEnv == "" {
 		cfg.OpenAI.APIKeyEnv = "OPENAI_API_KEY"
 	}
@@ -238,6 +383,9 @@ func Load(path string) (Config, error) {
 	if cfg.Radar.HistoryWindow.ToDuration() <= 0 {
 		cfg.Radar.HistoryWindow = Duration(5 * time.Minute)
 	}
+	if cfg.Radar.BarRetention <= 0 {
+		cfg.Radar.BarRetention = 500
+	}
 
 	// Cloud sanity (don’t override user values unless they are invalid)
 	if cfg.Cloud.EmitEvery.ToDuration() <= 0 {
@@ -288,5 +436,53 @@ func Load(path string) (Config, error) {
 		cfg.Cloud.NetBucketFlat = 20
 	}
 
+	if cfg.Simulator.AggEvery.ToDuration() <= 0 {
+		cfg.Simulator.AggEvery = Duration(time.Second)
+	}
+	if cfg.Simulator.StartPrice <= 0 {
+		cfg.Simulator.StartPrice = 100
+	}
+	for i, ev := range cfg.Simulator.Events {
+		// a move of -100% or more has no price to land on
+		if ev.MovePct <= -100 {
+			return cfg, fmt.Errorf("simulator.events[%d]: move_pct %g must be above -100", i, ev.MovePct)
+		}
+	}
+
+	if cfg.Recorder.Dir == "" {
+		cfg.Recorder.Dir = "./data/ticks"
+	}
+	if cfg.Recorder.MaxSizeMB < 0 {
+		cfg.Recorder.MaxSizeMB = 0
+	}
+
+	if cfg.Watchdog.SymbolStale.ToDuration() <= 0 {
+		cfg.Watchdog.SymbolStale = Duration(2 * time.Minute)
+	}
+	if cfg.Watchdog.FeedSilent.ToDuration() <= 0 {
+		cfg.Watchdog.FeedSilent = Duration(30 * time.Second)
+	}
+	if cfg.Watchdog.CheckEvery.ToDuration() <= 0 {
+		cfg.Watchdog.CheckEvery = Duration(5 * time.Second)
+	}
+
+	if cfg.Reference.RESTURL == "" {
+		cfg.Reference.RESTURL = "https://api.massive.com"
+	}
+	if cfg.Reference.Timeout.ToDuration() <= 0 {
+		cfg.Reference.Timeout = Duration(10 * time.Second)
+	}
+
+	if cfg.Session.Timezone == "" {
+		cfg.Session.Timezone = "America/New_York"
+	}
+
+	if cfg.State.Path == "" {
+		cfg.State.Path = "./data/radar-state.json"
+	}
+	if cfg.State.SaveEvery.ToDuration() <= 0 {
+		cfg.State.SaveEvery = Duration(30 * time.Second)
+	}
+
 	return cfg, nil
 }
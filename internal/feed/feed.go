@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"context"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindAgg   Kind = "agg"   // per-second aggregate (drives alerts + cloud)
+	KindTrade Kind = "trade" // individual print (cloud pulses, large print rule)
+	KindQuote Kind = "quote" // bid/ask update; Price is the midpoint (quote rules, forex cloud pulses)
+)
+
+// Tick is one normalized market update. It is also the line format of tick
+// files (recorder output / replay input), e.g.:
+//
+//	{"kind":"agg","sym":"MU","price":101.25,"volume":1200,"time":"2025-01-02T14:30:01Z"}
+type Tick struct {
+	Kind   Kind      `json:"kind"`
+	Symbol string    `json:"sym"`
+	Price  float64   `json:"price"`  // agg: close of the window; trade: print price
+	Volume float64   `json:"volume"` // agg: window volume; trade: print size
+	Time   time.Time `json:"time"`   // agg: window end; trade: print time
+
+	// Aggregate detail (zero for trades).
+	Open      float64   `json:"open,omitempty"`
+	High      float64   `json:"high,omitempty"`
+	Low       float64   `json:"low,omitempty"`
+	VWAP      float64   `json:"vwap,omitempty"`       // window VWAP
+	DayVWAP   float64   `json:"day_vwap,omitempty"`   // today's VWAP (stocks/options)
+	DayVolume float64   `json:"day_volume,omitempty"` // today's accumulated volume (stocks/options)
+	DayOpen   float64   `json:"day_open,omitempty"`   // today's official open (stocks/options, once known)
+	Start     time.Time `json:"-"`                    // window start (see Raw for the recorded value)
+
+	// Trade detail (zero for aggregates).
+	Exchange   int32   `json:"exchange,omitempty"`
+	Conditions []int32 `json:"conditions,omitempty"`
+
+	// Quote detail. Sizes are as sent by the feed (round lots for stocks/options).
+	Bid     float64 `json:"bid,omitempty"`
+	Ask     float64 `json:"ask,omitempty"`
+	BidSize float64 `json:"bid_size,omitempty"`
+	AskSize float64 `json:"ask_size,omitempty"`
+
+	// Raw is the original source message (nil for files/synthetic sources).
+	Raw any `json:"-"`
+}
+
+// MarketSource is anything that produces ticks: the Massive websocket,
+// a recorded file, a simulator, ...
+//
+// Subscribe may be called before or after Start. Ticks() is closed when the
+// source is finished (file exhausted, connection gone) or ctx is cancelled.
+// Errors() carries fatal errors (auth failures, unreadable files).
+type MarketSource interface {
+	Start(ctx context.Context) error
+	Subscribe(tickers ...string) error
+	Ticks() <-chan Tick
+	Errors() <-chan error
+}
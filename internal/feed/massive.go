@@ -0,0 +1,410 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	massivews "github.com/massive-com/client-go/v2/websocket"
+	wsmodels "github.com/massive-com/client-go/v2/websocket/models"
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/watchlist"
+)
+
+type MassiveConfig struct {
+	APIKey string
+	Feed   string // realtime | delayed
+	Market string // stocks | crypto | forex | options
+
+	// Also subscribe to trades (quotes for forex, which has no trades). These arrive at
+	// irregular times (unlike fixed 1s aggregates), which gives the cloud event-driven pulses.
+	Trades bool
+
+	// Tickers that get trades even when Trades is off (large_print rules).
+	TradeTickers []string
+
+	// Tickers that also get the NBBO quote stream (watchlist entries with quote rules
+	// or price_source: mid). Forex quotes already arrive as pulses when Trades is set.
+	QuoteTickers []string
+}
+
+// Massive is the live Massive websocket MarketSource for one market.
+// Use Merge to combine several markets.
+type Massive struct {
+	cfg    MassiveConfig
+	log    zerolog.Logger
+	ws     *massivews.Client
+	market string
+	topics marketTopics
+
+	ticks chan Tick
+	errs  chan error
+}
+
+// marketTopics are the websocket topics used per market.
+type marketTopics struct {
+	aggs       massivews.Topic
+	pulses     massivews.Topic // trades, or quotes where a market has no trades
+	pulsesName string
+	quotes     massivews.Topic
+}
+
+var topicsByMarket = map[string]marketTopics{
+	watchlist.MarketStocks:  {massivews.StocksSecAggs, massivews.StocksTrades, "stocks trades", massivews.StocksQuotes},
+	watchlist.MarketOptions: {massivews.OptionsSecAggs, massivews.OptionsTrades, "options trades", massivews.OptionsQuotes},
+	watchlist.MarketCrypto:  {massivews.CryptoSecAggs, massivews.CryptoTrades, "crypto trades", massivews.CryptoQuotes},
+	watchlist.MarketForex:   {massivews.ForexSecAggs, massivews.ForexQuotes, "forex quotes", massivews.ForexQuotes},
+}
+
+// Validate checks the config without creating a client; NewMassive runs the same check.
+func (cfg MassiveConfig) Validate() error {
+	if strings.TrimSpace(cfg.APIKey) == "" {
+		return errors.New("massive: API key is required")
+	}
+	return nil
+}
+
+func NewMassive(cfg MassiveConfig, log zerolog.Logger) (*Massive, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	market := watchlist.ParseMarket(cfg.Market)
+	// Keep the client's own retries short; longer outages are handled by a Supervisor
+	// (which can report them) rather than by Connect blocking for minutes.
+	retries := uint64(3)
+	ws, err := massivews.New(massivews.Config{
+		APIKey:     cfg.APIKey,
+		Feed:       parseMassiveFeed(cfg.Feed),
+		Market:     massivews.Market(market),
+		MaxRetries: &retries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Massive{
+		cfg:    cfg,
+		log:    log.With().Str("market", market).Logger(),
+		ws:     ws,
+		market: market,
+		topics: topicsByMarket[market],
+		ticks:  make(chan Tick, 4096),
+		errs:   make(chan error, 1),
+	}, nil
+}
+
+func (m *Massive) Ticks() <-chan Tick   { return m.ticks }
+func (m *Massive) Errors() <-chan error { return m.errs }
+
+// Start connects and pumps websocket messages into Ticks() until ctx is done
+// or the client's output channel closes.
+func (m *Massive) Start(ctx context.Context) error {
+	if err := m.ws.Connect(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(m.ticks)
+		defer m.ws.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case err := <-m.ws.Error():
+				// Fatal errors (auth, etc.)
+				select {
+				case m.errs <- err:
+				default:
+				}
+
+			case out, more := <-m.ws.Output():
+				if !more {
+					select {
+					case m.errs <- errors.New("massive websocket output channel closed"):
+					default:
+					}
+					return
+				}
+
+				// ignores other message types (status, LULD, imbalances, ...)
+				t, ok := tickFromMessage(out, m.market)
+				if !ok {
+					continue
+				}
+				t.Raw = out
+
+				select {
+				case m.ticks <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Subscribe subscribes to 1-second aggregates (and trades/quotes, if configured)
+// for the tickers that belong to this client's market; others are ignored.
+func (m *Massive) Subscribe(tickers ...string) error {
+	mine := make([]string, 0, len(tickers))
+	for _, t := range tickers {
+		if watchlist.MarketOf(t) == m.market {
+			mine = append(mine, t)
+		}
+	}
+	if len(mine) == 0 {
+		// an empty list would mean "all tickers" to the client
+		return nil
+	}
+
+	if err := m.ws.Subscribe(m.topics.aggs, mine...); err != nil {
+		return err
+	}
+
+	// If your account/topic permissions don’t allow trades, we just log and continue.
+	if m.cfg.Trades {
+		if err := m.ws.Subscribe(m.topics.pulses, mine...); err != nil {
+			m.log.Warn().Err(err).Msgf("could not subscribe to %s; cloud pulses will be less granular", m.topics.pulsesName)
+		} else {
+			m.log.Info().Msgf("subscribed to %s for event-driven cloud pulses", m.topics.pulsesName)
+		}
+	} else {
+		m.subscribeTrades(mine)
+	}
+
+	m.subscribeQuotes(mine)
+	return nil
+}
+
+// subscribeTrades subscribes trades for the subset of tickers whose rules need them
+// (only used when trades are not already subscribed for the cloud).
+func (m *Massive) subscribeTrades(tickers []string) {
+	if m.topics.pulses == m.topics.quotes {
+		return // no trades in this market (forex)
+	}
+	ts := subset(tickers, m.cfg.TradeTickers)
+	if len(ts) == 0 {
+		return
+	}
+	if err := m.ws.Subscribe(m.topics.pulses, ts...); err != nil {
+		m.log.Warn().Err(err).Strs("tickers", ts).Msgf("could not subscribe to %s; large print rules disabled", m.topics.pulsesName)
+		return
+	}
+	m.log.Info().Strs("tickers", ts).Msgf("subscribed to %s", m.topics.pulsesName)
+}
+
+// subscribeQuotes subscribes the NBBO stream for the subset of tickers that asked for it.
+// Like trades, a permission error only disables the quote rules.
+func (m *Massive) subscribeQuotes(tickers []string) {
+	if m.cfg.Trades && m.topics.quotes == m.topics.pulses {
+		return // already subscribed as pulses (forex)
+	}
+	qs := subset(tickers, m.cfg.QuoteTickers)
+	if len(qs) == 0 {
+		return
+	}
+	if err := m.ws.Subscribe(m.topics.quotes, qs...); err != nil {
+		m.log.Warn().Err(err).Strs("tickers", qs).Msg("could not subscribe to quotes; quote rules disabled")
+		return
+	}
+	m.log.Info().Strs("tickers", qs).Msg("subscribed to quotes")
+}
+
+// subset returns the tickers that are also listed in want, in order.
+func subset(tickers, want []string) []string {
+	in := make(map[string]bool, len(want))
+	for _, t := range want {
+		in[t] = true
+	}
+	var out []string
+	for _, t := range tickers {
+		if in[t] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func parseMassiveFeed(s string) massivews.Feed {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "realtime", "real_time", "real-time":
+		return massivews.RealTime
+	case "delayed":
+		return massivews.Delayed
+	default:
+		return massivews.RealTime
+	}
+}
+
+// tickFromMessage converts a typed Massive websocket message into a Tick whose
+// Symbol is the canonical watchlist ticker for market (e.g. pair "BTC-USD" -> "X:BTC-USD").
+// It reports false for message types that are not aggs/trades/quotes, or that lack a symbol or price.
+func tickFromMessage(msg any, market string) (Tick, bool) {
+	var t Tick
+	switch m := msg.(type) {
+	case wsmodels.EquityAgg:
+		t = tickFromEquityAgg(&m)
+	case *wsmodels.EquityAgg:
+		t = tickFromEquityAgg(m)
+	case wsmodels.EquityTrade:
+		t = tickFromEquityTrade(&m)
+	case *wsmodels.EquityTrade:
+		t = tickFromEquityTrade(m)
+	case wsmodels.CurrencyAgg:
+		t = tickFromCurrencyAgg(&m)
+	case *wsmodels.CurrencyAgg:
+		t = tickFromCurrencyAgg(m)
+	case wsmodels.CryptoTrade:
+		t = tickFromCryptoTrade(&m)
+	case *wsmodels.CryptoTrade:
+		t = tickFromCryptoTrade(m)
+	case wsmodels.ForexQuote:
+		t = tickFromForexQuote(&m)
+	case *wsmodels.ForexQuote:
+		t = tickFromForexQuote(m)
+	case wsmodels.EquityQuote:
+		t = tickFromEquityQuote(&m)
+	case *wsmodels.EquityQuote:
+		t = tickFromEquityQuote(m)
+	case wsmodels.CryptoQuote:
+		t = tickFromCryptoQuote(&m)
+	case *wsmodels.CryptoQuote:
+		t = tickFromCryptoQuote(m)
+	default:
+		return Tick{}, false
+	}
+
+	if strings.TrimSpace(t.Symbol) == "" || t.Price <= 0 {
+		return Tick{}, false
+	}
+	t.Symbol = watchlist.CanonicalTicker(t.Symbol, market)
+	if t.Time.IsZero() {
+		t.Time = time.Now()
+	}
+	return t, true
+}
+
+func tickFromEquityAgg(m *wsmodels.EquityAgg) Tick {
+	end := m.EndTimestamp
+	if end == 0 {
+		end = m.StartTimestamp
+	}
+	return Tick{
+		Kind:      KindAgg,
+		Symbol:    m.Symbol,
+		Price:     m.Close,
+		Volume:    m.Volume,
+		Time:      msTime(end),
+		Open:      m.Open,
+		High:      m.High,
+		Low:       m.Low,
+		VWAP:      m.VWAP,
+		DayVWAP:   m.AggregateVWAP,
+		DayVolume: m.AccumulatedVolume,
+		DayOpen:   m.OfficialOpenPrice,
+		Start:     msTime(m.StartTimestamp),
+	}
+}
+
+func tickFromEquityTrade(m *wsmodels.EquityTrade) Tick {
+	return Tick{
+		Kind:       KindTrade,
+		Symbol:     m.Symbol,
+		Price:      m.Price,
+		Volume:     float64(m.Size),
+		Time:       msTime(m.Timestamp),
+		Exchange:   m.Exchange,
+		Conditions: m.Conditions,
+	}
+}
+
+func tickFromCurrencyAgg(m *wsmodels.CurrencyAgg) Tick {
+	end := m.EndTimestamp
+	if end == 0 {
+		end = m.StartTimestamp
+	}
+	return Tick{
+		Kind:   KindAgg,
+		Symbol: m.Pair,
+		Price:  m.Close,
+		Volume: m.Volume,
+		Time:   msTime(end),
+		Open:   m.Open,
+		High:   m.High,
+		Low:    m.Low,
+		VWAP:   m.VWAP,
+		Start:  msTime(m.StartTimestamp),
+	}
+}
+
+func tickFromCryptoTrade(m *wsmodels.CryptoTrade) Tick {
+	return Tick{
+		Kind:       KindTrade,
+		Symbol:     m.Pair,
+		Price:      m.Price,
+		Volume:     m.Size,
+		Time:       msTime(m.Timestamp),
+		Exchange:   m.Exchange,
+		Conditions: m.Conditions,
+	}
+}
+
+func tickFromForexQuote(m *wsmodels.ForexQuote) Tick {
+	return Tick{
+		Kind:     KindQuote,
+		Symbol:   m.Pair,
+		Price:    midpoint(m.BidPrice, m.AskPrice),
+		Time:     msTime(m.Timestamp),
+		Exchange: m.ExchangeID,
+		Bid:      m.BidPrice,
+		Ask:      m.AskPrice,
+	}
+}
+
+func tickFromEquityQuote(m *wsmodels.EquityQuote) Tick {
+	return Tick{
+		Kind:    KindQuote,
+		Symbol:  m.Symbol,
+		Price:   midpoint(m.BidPrice, m.AskPrice),
+		Time:    msTime(m.Timestamp),
+		Bid:     m.BidPrice,
+		Ask:     m.AskPrice,
+		BidSize: float64(m.BidSize),
+		AskSize: float64(m.AskSize),
+	}
+}
+
+func tickFromCryptoQuote(m *wsmodels.CryptoQuote) Tick {
+	return Tick{
+		Kind:     KindQuote,
+		Symbol:   m.Pair,
+		Price:    midpoint(m.BidPrice, m.AskPrice),
+		Time:     msTime(m.Timestamp),
+		Exchange: m.ExchangeID,
+		Bid:      m.BidPrice,
+		Ask:      m.AskPrice,
+		BidSize:  m.BidSize,
+		AskSize:  m.AskSize,
+	}
+}
+
+// midpoint is 0 unless both sides of the book are present.
+func midpoint(bid, ask float64) float64 {
+	if bid <= 0 || ask <= 0 {
+		return 0
+	}
+	return (bid + ask) / 2
+}
+
+// msTime converts a Unix-millisecond timestamp; 0 stays the zero time.
+func msTime(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,225 @@
+package feed
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	wsmodels "github.com/massive-com/client-go/v2/websocket/models"
+
+	"stockradar/internal/watchlist"
+)
+
+const (
+	testStart = int64(1760535000000) // 2025-10-15 13:30:00 UTC
+	testEnd   = testStart + 1000
+)
+
+func TestTickFromMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    any
+		market string
+		want   Tick
+	}{
+		{
+			name: "equity agg",
+			msg: &wsmodels.EquityAgg{
+				Symbol: "mu", Open: 100, High: 101, Low: 99.5, Close: 100.5,
+				Volume: 1200, VWAP: 100.4, AggregateVWAP: 99.8, AccumulatedVolume: 500000,
+				OfficialOpenPrice: 98.75, StartTimestamp: testStart, EndTimestamp: testEnd,
+			},
+			market: watchlist.MarketStocks,
+			want: Tick{
+				Kind: KindAgg, Symbol: "MU", Price: 100.5, Volume: 1200, Time: time.UnixMilli(testEnd),
+				Open: 100, High: 101, Low: 99.5, VWAP: 100.4, DayVWAP: 99.8, DayVolume: 500000,
+				DayOpen: 98.75, Start: time.UnixMilli(testStart),
+			},
+		},
+		{
+			name:   "equity agg without end uses start",
+			msg:    wsmodels.EquityAgg{Symbol: "MU", Close: 100.5, StartTimestamp: testStart},
+			market: watchlist.MarketStocks,
+			want: Tick{
+				Kind: KindAgg, Symbol: "MU", Price: 100.5, Time: time.UnixMilli(testStart),
+				Start: time.UnixMilli(testStart),
+			},
+		},
+		{
+			name: "equity trade",
+			msg: &wsmodels.EquityTrade{
+				Symbol: "MU", Price: 100.25, Size: 300, Exchange: 4, Conditions: []int32{12, 37},
+				Timestamp: testEnd,
+			},
+			market: watchlist.MarketStocks,
+			want: Tick{
+				Kind: KindTrade, Symbol: "MU", Price: 100.25, Volume: 300, Time: time.UnixMilli(testEnd),
+				Exchange: 4, Conditions: []int32{12, 37},
+			},
+		},
+		{
+			name: "equity quote",
+			msg: &wsmodels.EquityQuote{
+				Symbol: "MU", BidPrice: 100.2, AskPrice: 100.3, BidSize: 3, AskSize: 5, Timestamp: testEnd,
+			},
+			market: watchlist.MarketStocks,
+			want: Tick{
+				Kind: KindQuote, Symbol: "MU", Price: (100.2 + 100.3) / 2, Time: time.UnixMilli(testEnd),
+				Bid: 100.2, Ask: 100.3, BidSize: 3, AskSize: 5,
+			},
+		},
+		{
+			name: "currency agg",
+			msg: &wsmodels.CurrencyAgg{
+				Pair: "BTC-USD", Open: 60000, High: 60100, Low: 59900, Close: 60050,
+				Volume: 1.5, VWAP: 60010, StartTimestamp: testStart, EndTimestamp: testEnd,
+			},
+			market: watchlist.MarketCrypto,
+			want: Tick{
+				Kind: KindAgg, Symbol: "X:BTC-USD", Price: 60050, Volume: 1.5, Time: time.UnixMilli(testEnd),
+				Open: 60000, High: 60100, Low: 59900, VWAP: 60010, Start: time.UnixMilli(testStart),
+			},
+		},
+		{
+			name: "crypto trade",
+			msg: &wsmodels.CryptoTrade{
+				Pair: "BTC-USD", Price: 60050, Size: 0.25, Exchange: 1, Conditions: []int32{2},
+				Timestamp: testEnd,
+			},
+			market: watchlist.MarketCrypto,
+			want: Tick{
+				Kind: KindTrade, Symbol: "X:BTC-USD", Price: 60050, Volume: 0.25, Time: time.UnixMilli(testEnd),
+				Exchange: 1, Conditions: []int32{2},
+			},
+		},
+		{
+			name: "crypto quote",
+			msg: &wsmodels.CryptoQuote{
+				Pair: "BTC-USD", BidPrice: 60040, AskPrice: 60060, BidSize: 0.5, AskSize: 1.25,
+				ExchangeID: 1, Timestamp: testEnd,
+			},
+			market: watchlist.MarketCrypto,
+			want: Tick{
+				Kind: KindQuote, Symbol: "X:BTC-USD", Price: 60050, Time: time.UnixMilli(testEnd),
+				Exchange: 1, Bid: 60040, Ask: 60060, BidSize: 0.5, AskSize: 1.25,
+			},
+		},
+		{
+			name: "forex quote",
+			msg: &wsmodels.ForexQuote{
+				Pair: "EUR/USD", BidPrice: 1.0850, AskPrice: 1.0852, ExchangeID: 48, Timestamp: testEnd,
+			},
+			market: watchlist.MarketForex,
+			want: Tick{
+				Kind: KindQuote, Symbol: "C:EUR-USD", Price: (1.0850 + 1.0852) / 2, Time: time.UnixMilli(testEnd),
+				Exchange: 48, Bid: 1.0850, Ask: 1.0852,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tickFromMessage(tt.msg, tt.market)
+			if !ok {
+				t.Fatalf("tickFromMessage rejected %T", tt.msg)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tickFromMessage =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTickFromMessageRejects(t *testing.T) {
+	for _, msg := range []any{
+		wsmodels.EquityTrade{Symbol: "", Price: 100},
+		wsmodels.EquityTrade{Symbol: "MU", Price: 0},
+		wsmodels.EquityQuote{Symbol: "MU", BidPrice: 100}, // one-sided book has no midpoint
+		wsmodels.ControlMessage{},
+	} {
+		if tk, ok := tickFromMessage(msg, watchlist.MarketStocks); ok {
+			t.Errorf("tickFromMessage(%T) = %+v, want rejected", msg, tk)
+		}
+	}
+}
+
+// BenchmarkTickFromMessage compares the typed extraction with the JSON round-trip
+// it replaced (tickFromJSON):
+//
+//	go test ./internal/feed -run - -bench TickFromMessage -benchmem
+func BenchmarkTickFromMessage(b *testing.B) {
+	msgs := []struct {
+		name string
+		msg  any
+	}{
+		{"agg", &wsmodels.EquityAgg{
+			Symbol: "MU", Open: 100, High: 101, Low: 99.5, Close: 100.5, Volume: 1200,
+			VWAP: 100.4, AggregateVWAP: 99.8, AccumulatedVolume: 500000,
+			StartTimestamp: testStart, EndTimestamp: testEnd,
+		}},
+		{"trade", &wsmodels.EquityTrade{
+			Symbol: "MU", Price: 100.25, Size: 300, Exchange: 4, Conditions: []int32{12, 37}, Timestamp: testEnd,
+		}},
+	}
+	for _, m := range msgs {
+		b.Run(m.name+"/typed", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, ok := tickFromMessage(m.msg, watchlist.MarketStocks); !ok {
+					b.Fatal("rejected")
+				}
+			}
+		})
+		b.Run(m.name+"/json", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, ok := tickFromJSON(m.msg); !ok {
+					b.Fatal("rejected")
+				}
+			}
+		})
+	}
+}
+
+// tickFromJSON is the decoder tickFromMessage replaced, kept as the benchmark
+// baseline: marshal the message to JSON, unmarshal into a map and pick keys.
+func tickFromJSON(v any) (Tick, bool) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return Tick{}, false
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return Tick{}, false
+	}
+	// first key holding a value of the wanted type, as the old pickString / pickFloat did
+	pickString := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := m[k].(string); ok {
+				return v
+			}
+		}
+		return ""
+	}
+	pickFloat := func(keys ...string) float64 {
+		for _, k := range keys {
+			if v, ok := m[k].(float64); ok {
+				return v
+			}
+		}
+		return 0
+	}
+	sym := pickString("sym", "Sym", "symbol", "Symbol", "ticker", "Ticker", "T")
+	price := pickFloat("c", "C", "close", "Close", "price", "Price", "p", "P")
+	vol := pickFloat("v", "V", "volume", "Volume", "size", "Size", "qty", "Qty", "shares", "Shares", "s", "S", "q", "Q")
+	ts := time.Now()
+	if ms := pickFloat("e", "E", "end", "End", "t", "T", "timestamp", "Timestamp"); ms > 0 {
+		ts = time.UnixMilli(int64(ms))
+	}
+	sym = strings.ToUpper(strings.TrimSpace(sym))
+	if sym == "" || price <= 0 {
+		return Tick{}, false
+	}
+	return Tick{Symbol: sym, Price: price, Volume: vol, Time: ts}, true
+}
@@ -0,0 +1,75 @@
+package feed
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge combines several sources (e.g. one Massive client per market) into one.
+// Subscribe is forwarded to every source; each source ignores tickers it does not serve.
+func Merge(srcs ...MarketSource) MarketSource {
+	return &merged{
+		srcs:  srcs,
+		ticks: make(chan Tick, 4096),
+		errs:  make(chan error, len(srcs)),
+	}
+}
+
+type merged struct {
+	srcs  []MarketSource
+	ticks chan Tick
+	errs  chan error
+}
+
+func (m *merged) Ticks() <-chan Tick   { return m.ticks }
+func (m *merged) Errors() <-chan error { return m.errs }
+
+func (m *merged) Subscribe(tickers ...string) error {
+	for _, s := range m.srcs {
+		if err := s.Subscribe(tickers...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *merged) Start(ctx context.Context) error {
+	for _, s := range m.srcs {
+		if err := s.Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range m.srcs {
+		wg.Add(1)
+		go func(s MarketSource) {
+			defer wg.Done()
+			errs := s.Errors()
+			for {
+				select {
+				case t, more := <-s.Ticks():
+					if !more {
+						return
+					}
+					select {
+					case m.ticks <- t:
+					case <-ctx.Done():
+						return
+					}
+				case err := <-errs:
+					select {
+					case m.errs <- err:
+					default:
+					}
+				}
+			}
+		}(s)
+	}
+
+	go func() {
+		wg.Wait()
+		close(m.ticks)
+	}()
+	return nil
+}
@@ -0,0 +1,265 @@
+package feed
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/watchlist"
+)
+
+type SimConfig struct {
+	AggEvery time.Duration // aggregate cadence (1s matches StocksSecAggs)
+	Step     time.Duration // price-path resolution
+
+	DriftPct      float64 // expected drift, % per hour
+	VolatilityPct float64 // stdev of returns, % per minute
+	Correlation   float64 // 0..1 share of each shock common to all symbols
+
+	TradesPerSec  float64 // mean trade prints per symbol per second (0 disables trades)
+	MeanTradeSize float64
+
+	StartPrice float64
+	Prices     map[string]float64 // per-symbol start price overrides
+
+	Seed   int64 // 0 = random
+	Events []SimEvent
+}
+
+// SimEvent is a scripted move, e.g. "MU drops 2% at t+30s":
+//
+//	{At: 30s, Symbol: "MU", MovePct: -2, Over: 5s}
+//
+// An empty Symbol (or "*") applies the move to every symbol.
+type SimEvent struct {
+	At      time.Duration
+	Symbol  string
+	MovePct float64
+	Over    time.Duration // 0 = instant jump
+}
+
+// Simulator is a synthetic MarketSource: correlated geometric Brownian motion
+// per symbol, Poisson trade prints, fixed-cadence aggregates, plus scripted events.
+type Simulator struct {
+	cfg SimConfig
+	log zerolog.Logger
+
+	ticks chan Tick
+	errs  chan error
+
+	mu    sync.Mutex
+	syms  map[string]*simSym
+	order []string // subscription order, so a fixed Seed reproduces the same paths
+}
+
+type simSym struct {
+	price float64
+	vol   float64 // traded volume since the last aggregate
+	pair  bool    // crypto/forex: quoted to significant digits, not cents
+}
+
+func NewSimulator(cfg SimConfig, log zerolog.Logger) *Simulator {
+	if cfg.AggEvery <= 0 {
+		cfg.AggEvery = time.Second
+	}
+	if cfg.Step <= 0 {
+		cfg.Step = 100 * time.Millisecond
+	}
+	if cfg.Step > cfg.AggEvery {
+		cfg.Step = cfg.AggEvery
+	}
+	if cfg.VolatilityPct < 0 {
+		cfg.VolatilityPct = -cfg.VolatilityPct
+	}
+	if cfg.Correlation < 0 {
+		cfg.Correlation = 0
+	}
+	if cfg.Correlation > 1 {
+		cfg.Correlation = 1
+	}
+	if cfg.TradesPerSec < 0 {
+		cfg.TradesPerSec = 0
+	}
+	if cfg.MeanTradeSize <= 0 {
+		cfg.MeanTradeSize = 100
+	}
+	if cfg.StartPrice <= 0 {
+		cfg.StartPrice = 100
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return &Simulator{
+		cfg:   cfg,
+		log:   log,
+		ticks: make(chan Tick, 4096),
+		errs:  make(chan error, 1),
+		syms:  map[string]*simSym{},
+	}
+}
+
+func (s *Simulator) Ticks() <-chan Tick   { return s.ticks }
+func (s *Simulator) Errors() <-chan error { return s.errs }
+
+// Subscribe adds symbols to the simulation.
+func (s *Simulator) Subscribe(tickers ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range tickers {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if t == "" || s.syms[t] != nil {
+			continue
+		}
+		p := s.cfg.StartPrice
+		if v, ok := s.cfg.Prices[t]; ok && v > 0 {
+			p = v
+		}
+		m := watchlist.MarketOf(t)
+		s.syms[t] = &simSym{price: p, pair: m == watchlist.MarketCrypto || m == watchlist.MarketForex}
+		s.order = append(s.order, t)
+	}
+	return nil
+}
+
+func (s *Simulator) Start(ctx context.Context) error {
+	s.log.Info().
+		Int64("seed", s.cfg.Seed).
+		Float64("volatility_pct", s.cfg.VolatilityPct).
+		Float64("correlation", s.cfg.Correlation).
+		Int("events", len(s.cfg.Events)).
+		Msg("market simulator started")
+
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Simulator) run(ctx context.Context) {
+	defer close(s.ticks)
+
+	rng := rand.New(rand.NewSource(s.cfg.Seed))
+
+	dt := s.cfg.Step.Seconds()
+	// per-step drift/vol in log-return units
+	mu := s.cfg.DriftPct / 100.0 / 3600.0 * dt
+	sigma := s.cfg.VolatilityPct / 100.0 / math.Sqrt(60.0) * math.Sqrt(dt)
+	rho := s.cfg.Correlation
+	pTrade := s.cfg.TradesPerSec * dt
+
+	start := time.Now()
+	var prevElapsed time.Duration
+	nextAgg := start.Add(s.cfg.AggEvery)
+
+	tk := time.NewTicker(s.cfg.Step)
+	defer tk.Stop()
+
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-tk.C:
+		}
+		elapsed := now.Sub(start)
+
+		common := rng.NormFloat64()
+
+		s.mu.Lock()
+		var out []Tick
+		for _, sym := range s.order {
+			st := s.syms[sym]
+			z := math.Sqrt(rho)*common + math.Sqrt(1-rho)*rng.NormFloat64()
+			r := mu - 0.5*sigma*sigma + sigma*z
+			r += s.eventReturn(sym, prevElapsed, elapsed)
+
+			st.price *= math.Exp(r)
+
+			if pTrade > 0 && rng.Float64() < pTrade {
+				size := math.Max(1, math.Round(rng.ExpFloat64()*s.cfg.MeanTradeSize))
+				st.vol += size
+				out = append(out, Tick{Kind: KindTrade, Symbol: sym, Price: roundPrice(st.price, st.pair), Volume: size, Time: now})
+			}
+		}
+
+		if !now.Before(nextAgg) {
+			for _, sym := range s.order {
+				st := s.syms[sym]
+				vol := st.vol
+				if s.cfg.TradesPerSec == 0 {
+					// no trade stream: still give aggregates a plausible volume
+					vol = math.Round(rng.ExpFloat64() * s.cfg.MeanTradeSize * s.cfg.AggEvery.Seconds())
+				}
+				out = append(out, Tick{Kind: KindAgg, Symbol: sym, Price: roundPrice(st.price, st.pair), Volume: vol, Time: now})
+				st.vol = 0
+			}
+			nextAgg = nextAgg.Add(s.cfg.AggEvery)
+		}
+		s.mu.Unlock()
+		prevElapsed = elapsed
+
+		for _, t := range out {
+			select {
+			case s.ticks <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// eventReturn is the scripted log-return for sym over the step (from, to]; the
+// first step (from = 0) also includes 0, so an event at "0s" fires at start.
+func (s *Simulator) eventReturn(sym string, from, to time.Duration) float64 {
+	r := 0.0
+	for _, ev := range s.cfg.Events {
+		if ev.Symbol != "" && ev.Symbol != "*" && !strings.EqualFold(ev.Symbol, sym) {
+			continue
+		}
+		total := math.Log(1 + ev.MovePct/100.0)
+		if ev.Over <= 0 {
+			if (ev.At > from || from == 0) && ev.At <= to {
+				r += total
+			}
+			continue
+		}
+		// spread the move evenly over [At, At+Over]
+		lo := maxDur(from, ev.At)
+		hi := minDur(to, ev.At+ev.Over)
+		if hi > lo {
+			r += total * float64(hi-lo) / float64(ev.Over)
+		}
+	}
+	return r
+}
+
+// roundPrice rounds a simulated price to a plausible quote increment: cents for
+// stocks and options from $1 (hundredths of a cent below), six significant
+// digits for crypto and forex pairs (1.08512, 61234.5).
+func roundPrice(x float64, pair bool) float64 {
+	scale := 100.0
+	switch {
+	case pair && x > 0:
+		scale = math.Pow(10, 5-math.Floor(math.Log10(x)))
+	case x < 1:
+		scale = 10000
+	}
+	return math.Round(x*scale) / scale
+}
+
+func maxDur(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minDur(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
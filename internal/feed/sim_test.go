@@ -0,0 +1,40 @@
+package feed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRoundPrice(t *testing.T) {
+	tests := []struct {
+		x    float64
+		pair bool
+		want float64
+	}{
+		{101.23456, false, 101.23},
+		{0.123456, false, 0.1235},
+		{1.0851234, true, 1.08512},
+		{151.23456, true, 151.235},
+		{61234.567, true, 61234.6},
+		{0.00001234567, true, 0.0000123457},
+	}
+	for _, tt := range tests {
+		if got := roundPrice(tt.x, tt.pair); got != tt.want {
+			t.Errorf("roundPrice(%v, %v) = %v, want %v", tt.x, tt.pair, got, tt.want)
+		}
+	}
+}
+
+func TestEventReturnAtStart(t *testing.T) {
+	s := NewSimulator(SimConfig{Events: []SimEvent{{At: 0, Symbol: "MU", MovePct: 10}}}, zerolog.Nop())
+	step := 100 * time.Millisecond
+	if got, want := s.eventReturn("MU", 0, step), math.Log(1.1); got != want {
+		t.Errorf("first step return = %v, want %v", got, want)
+	}
+	if got := s.eventReturn("MU", step, 2*step); got != 0 {
+		t.Errorf("second step return = %v, want 0", got)
+	}
+}
@@ -0,0 +1,236 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type State string
+
+const (
+	StateConnecting   State = "connecting"   // first connection attempt
+	StateConnected    State = "connected"    // subscribed and streaming
+	StateReconnecting State = "reconnecting" // lost (or never came up); waiting to retry
+	StateStopped      State = "stopped"      // ctx cancelled
+)
+
+// Status is a supervisor state change.
+type Status struct {
+	Name    string // e.g. market ("stocks")
+	State   State
+	Prev    State
+	Attempt int           // consecutive failed attempts; 0 once connected
+	RetryIn time.Duration // delay before the next attempt (reconnecting only)
+	Err     error         // why the connection was lost / could not be made
+	Time    time.Time
+	UpSince time.Time // last successful connect (zero if never)
+}
+
+type SupervisorConfig struct {
+	Name       string
+	MinBackoff time.Duration // first retry delay (doubles per failure)
+	MaxBackoff time.Duration
+
+	// OnStatus is called (from the supervisor goroutine) on every state change.
+	OnStatus func(Status)
+}
+
+// Supervisor is a MarketSource that keeps another source alive: when the inner
+// source fails to start, reports an error, or closes its ticks, the supervisor
+// discards it, waits with exponential backoff, creates a fresh one via dial and
+// resubscribes every ticker subscribed so far.
+//
+// Its Ticks() only close when ctx is cancelled; Errors() never fires, since
+// every failure is retried (see OnStatus instead).
+type Supervisor struct {
+	cfg  SupervisorConfig
+	log  zerolog.Logger
+	dial func() (MarketSource, error)
+
+	ticks chan Tick
+	errs  chan error
+
+	mu      sync.Mutex
+	tickers []string
+	seen    map[string]bool
+	cur     MarketSource
+	status  Status
+}
+
+func NewSupervisor(cfg SupervisorConfig, dial func() (MarketSource, error), log zerolog.Logger) *Supervisor {
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = 60 * time.Second
+		if cfg.MaxBackoff < cfg.MinBackoff {
+			cfg.MaxBackoff = cfg.MinBackoff
+		}
+	}
+	return &Supervisor{
+		cfg:   cfg,
+		log:   log.With().Str("feed", cfg.Name).Logger(),
+		dial:  dial,
+		ticks: make(chan Tick, 4096),
+		errs:  make(chan error),
+		seen:  map[string]bool{},
+	}
+}
+
+func (s *Supervisor) Ticks() <-chan Tick   { return s.ticks }
+func (s *Supervisor) Errors() <-chan error { return s.errs }
+
+// Status returns the latest state.
+func (s *Supervisor) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Subscribe records tickers (for resubscription) and forwards them to the
+// current connection, if any.
+func (s *Supervisor) Subscribe(tickers ...string) error {
+	s.mu.Lock()
+	var added []string
+	for _, t := range tickers {
+		if t == "" || s.seen[t] {
+			continue
+		}
+		s.seen[t] = true
+		s.tickers = append(s.tickers, t)
+		added = append(added, t)
+	}
+	cur := s.cur
+	s.mu.Unlock()
+
+	if cur == nil || len(added) == 0 {
+		return nil
+	}
+	return cur.Subscribe(added...)
+}
+
+// Start never fails: connection errors are retried in the background.
+func (s *Supervisor) Start(ctx context.Context) error {
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Supervisor) run(ctx context.Context) {
+	defer close(s.ticks)
+
+	s.setStatus(Status{State: StateConnecting})
+
+	attempt := 0
+	var upSince time.Time
+	for {
+		err := s.session(ctx, &upSince, &attempt)
+		if ctx.Err() != nil {
+			s.setStatus(Status{State: StateStopped, UpSince: upSince})
+			return
+		}
+
+		attempt++
+		delay := s.backoff(attempt)
+		s.log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", delay).Msg("market feed down; reconnecting")
+		s.setStatus(Status{State: StateReconnecting, Attempt: attempt, RetryIn: delay, Err: err, UpSince: upSince})
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			s.setStatus(Status{State: StateStopped, UpSince: upSince})
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// session runs one inner source from dial to failure and reports why it ended.
+func (s *Supervisor) session(ctx context.Context, upSince *time.Time, attempt *int) error {
+	src, err := s.dial()
+	if err != nil {
+		return err
+	}
+
+	// The inner source gets its own ctx so a failed one is torn down before the next dial.
+	sctx, scancel := context.WithCancel(ctx)
+	defer scancel()
+
+	if err := src.Start(sctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	tickers := append([]string(nil), s.tickers...)
+	s.cur = src
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.cur = nil
+		s.mu.Unlock()
+	}()
+
+	if len(tickers) > 0 {
+		if err := src.Subscribe(tickers...); err != nil {
+			return err
+		}
+	}
+
+	*attempt = 0
+	*upSince = time.Now()
+	s.setStatus(Status{State: StateConnected, UpSince: *upSince})
+
+	in := src.Ticks()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-src.Errors():
+			if err == nil {
+				err = errors.New("market source reported an error")
+			}
+			return err
+		case t, more := <-in:
+			if !more {
+				return errors.New("market source closed")
+			}
+			select {
+			case s.ticks <- t:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// backoff is MinBackoff * 2^(attempt-1), capped at MaxBackoff, with ±20% jitter
+// so several markets do not reconnect in lockstep.
+func (s *Supervisor) backoff(attempt int) time.Duration {
+	d := s.cfg.MinBackoff
+	for i := 1; i < attempt && d < s.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > s.cfg.MaxBackoff {
+		d = s.cfg.MaxBackoff
+	}
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(d) * jitter)
+}
+
+func (s *Supervisor) setStatus(st Status) {
+	s.mu.Lock()
+	st.Name = s.cfg.Name
+	st.Prev = s.status.State
+	st.Time = time.Now()
+	s.status = st
+	s.mu.Unlock()
+
+	if s.cfg.OnStatus != nil {
+		s.cfg.OnStatus(st)
+	}
+}
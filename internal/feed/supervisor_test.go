@@ -0,0 +1,194 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeSource is a MarketSource the test drives: it records subscriptions and the
+// ctx it was started with, and fails when the test closes ticks or sends an error.
+type fakeSource struct {
+	ticks chan Tick
+	errs  chan error
+
+	mu   sync.Mutex
+	subs []string
+	ctx  context.Context
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{ticks: make(chan Tick), errs: make(chan error, 1)}
+}
+
+func (f *fakeSource) Start(ctx context.Context) error {
+	f.mu.Lock()
+	f.ctx = ctx
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeSource) Subscribe(tickers ...string) error {
+	f.mu.Lock()
+	f.subs = append(f.subs, tickers...)
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeSource) Ticks() <-chan Tick   { return f.ticks }
+func (f *fakeSource) Errors() <-chan error { return f.errs }
+
+func (f *fakeSource) subscribed() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.subs...)
+}
+
+func (f *fakeSource) stopped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ctx != nil && f.ctx.Err() != nil
+}
+
+func TestSupervisorReconnects(t *testing.T) {
+	first, second, third := newFakeSource(), newFakeSource(), newFakeSource()
+	var mu sync.Mutex
+	dials := []func() (MarketSource, error){
+		func() (MarketSource, error) { return nil, errors.New("dial failed") },
+		func() (MarketSource, error) { return first, nil },
+		func() (MarketSource, error) { return second, nil },
+		func() (MarketSource, error) { return third, nil },
+	}
+	dial := func() (MarketSource, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(dials) == 0 {
+			return nil, errors.New("no more sources")
+		}
+		d := dials[0]
+		dials = dials[1:]
+		return d()
+	}
+
+	statuses := make(chan Status, 100)
+	s := NewSupervisor(SupervisorConfig{
+		Name:       "stocks",
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+		OnStatus:   func(st Status) { statuses <- st },
+	}, dial, zerolog.Nop())
+
+	wait := func(want State) Status {
+		t.Helper()
+		for {
+			select {
+			case st := <-statuses:
+				if st.State == want {
+					return st
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out waiting for %s", want)
+			}
+		}
+	}
+	recv := func(want string) {
+		t.Helper()
+		select {
+		case tk := <-s.Ticks():
+			if tk.Symbol != want {
+				t.Fatalf("tick %s, want %s", tk.Symbol, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for a %s tick", want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Subscribe("MU"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// a failed dial is retried
+	wait(StateConnecting)
+	if st := wait(StateReconnecting); st.Attempt != 1 || st.Err == nil {
+		t.Errorf("after a failed dial: %+v, want attempt 1 with the error", st)
+	}
+	wait(StateConnected)
+	if err := s.Subscribe("QQQ"); err != nil { // while connected: forwarded
+		t.Fatal(err)
+	}
+	if got, want := first.subscribed(), []string{"MU", "QQQ"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first source subscribed %v, want %v", got, want)
+	}
+	first.ticks <- Tick{Symbol: "MU"}
+	recv("MU")
+
+	// closed ticks: the source is torn down and a new one gets every ticker
+	close(first.ticks)
+	if st := wait(StateReconnecting); st.Attempt != 1 {
+		t.Errorf("attempt after a connected source closed = %d, want 1 (reset on connect)", st.Attempt)
+	}
+	wait(StateConnected)
+	if !first.stopped() {
+		t.Error("first source's ctx not cancelled after it failed")
+	}
+	if got, want := second.subscribed(), []string{"MU", "QQQ"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second source subscribed %v, want %v", got, want)
+	}
+	second.ticks <- Tick{Symbol: "QQQ"}
+	recv("QQQ")
+
+	// a reported error is retried too
+	second.errs <- errors.New("auth expired")
+	wait(StateReconnecting)
+	wait(StateConnected)
+	third.ticks <- Tick{Symbol: "MU"}
+	recv("MU")
+
+	// shutdown closes Ticks and stops the inner source
+	cancel()
+	wait(StateStopped)
+	select {
+	case _, more := <-s.Ticks():
+		if more {
+			t.Error("tick after shutdown")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Ticks not closed after shutdown")
+	}
+	if !third.stopped() {
+		t.Error("current source's ctx not cancelled on shutdown")
+	}
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	s := NewSupervisor(SupervisorConfig{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, nil, zerolog.Nop())
+	for _, tt := range []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{20, time.Second},
+	} {
+		lo, hi := time.Duration(float64(tt.base)*0.8), time.Duration(float64(tt.base)*1.2)
+		for i := 0; i < 20; i++ {
+			if d := s.backoff(tt.attempt); d < lo || d > hi {
+				t.Errorf("backoff(%d) = %s, want %s-%s", tt.attempt, d, lo, hi)
+				break
+			}
+		}
+	}
+}
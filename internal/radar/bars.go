@@ -0,0 +1,121 @@
+package radar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// bar is one OHLC bar of the symbol's reference price.
+type bar struct {
+	start                  time.Time
+	open, high, low, close float64
+}
+
+// barSeries resamples prices into fixed-length bars aligned to the period and
+// keeps the last max closed bars. Periods without ticks produce no bar.
+type barSeries struct {
+	period time.Duration
+	max    int
+	closed []bar
+	cur    bar // open bar (zero start = none yet)
+}
+
+func newBarSeries(period time.Duration, keep int) *barSeries {
+	return &barSeries{period: period, max: keep}
+}
+
+// add folds a price into the open bar and reports whether the previous bar
+// closed (p is the first tick of a later period).
+func (s *barSeries) add(p float64, ts time.Time) bool {
+	start := ts.Truncate(s.period)
+	switch {
+	case s.cur.start.IsZero():
+		s.cur = bar{start: start, open: p, high: p, low: p, close: p}
+		return false
+	case start.Before(s.cur.start):
+		return false // late tick from another stream; its bar is gone
+	case start.Equal(s.cur.start):
+		s.cur.high = max(s.cur.high, p)
+		s.cur.low = min(s.cur.low, p)
+		s.cur.close = p
+		return false
+	}
+
+	s.closed = append(s.closed, s.cur)
+	if n := len(s.closed); n > s.max {
+		s.closed = append(s.closed[:0], s.closed[n-s.max:]...)
+	}
+	s.cur = bar{start: start, open: p, high: p, low: p, close: p}
+	return true
+}
+
+// feedBars adds the price to each bar series the symbol's rules use (ma_cross
+// bars, the volatility bar), one series per bar length, and reports which of
+// them closed a bar.
+func (e *Engine) feedBars(ws *watchlist.Symbol, st *symbolState, price float64, ts time.Time) map[time.Duration]bool {
+	var lengths []time.Duration
+	for _, r := range ws.MACross {
+		lengths = append(lengths, r.Bar.ToDuration())
+	}
+	if v := ws.Volatility; v != nil {
+		lengths = append(lengths, v.Bar.ToDuration())
+	}
+
+	closed := map[time.Duration]bool{}
+	for _, d := range lengths {
+		if _, done := closed[d]; done {
+			continue
+		}
+		s := st.bars[d]
+		if s == nil {
+			s = newBarSeries(d, e.cfg.BarRetention)
+			st.bars[d] = s
+		}
+		closed[d] = s.add(price, ts)
+	}
+	return closed
+}
+
+// movingAverage is the n-bar average of closes over bars[:end] (ok false if too few bars).
+// The EMA is seeded with the SMA of the oldest n retained bars.
+func movingAverage(typ string, bars []bar, n, end int) (float64, bool) {
+	if n <= 0 || end < n || end > len(bars) {
+		return 0, false
+	}
+	if typ != watchlist.MATypeEMA {
+		sum := 0.0
+		for _, b := range bars[end-n : end] {
+			sum += b.close
+		}
+		return sum / float64(n), true
+	}
+
+	k := 2 / float64(n+1)
+	v, _ := movingAverage(watchlist.MATypeSMA, bars, n, n)
+	for _, b := range bars[n:end] {
+		v = b.close*k + v*(1-k)
+	}
+	return v, true
+}
+
+// barLabel names a bar length: "1m" / "1 minute", "30s" / "30 second".
+func barLabel(d time.Duration) (short, spoken string) {
+	short = d.String()
+	if strings.HasSuffix(short, "m0s") {
+		short = strings.TrimSuffix(short, "0s")
+	}
+	if strings.HasSuffix(short, "h0m") {
+		short = strings.TrimSuffix(short, "0m")
+	}
+	switch {
+	case d%time.Hour == 0:
+		return short, fmt.Sprintf("%d hour", int(d.Hours()))
+	case d%time.Minute == 0:
+		return short, fmt.Sprintf("%d minute", int(d.Minutes()))
+	default:
+		return short, fmt.Sprintf("%d second", int(d.Seconds()))
+	}
+}
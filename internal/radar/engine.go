@@ -8,6 +8,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"stockradar/internal/session"
 	"stockradar/internal/watchlist"
 )
 
@@ -20,12 +21,50 @@ const (
 	AlertMomentumDown AlertType = "momentum_down"
 	AlertCrossAbove  AlertType = "cross_above"
 	AlertCrossBelow  AlertType = "cross_below"
+
+	// Quote (NBBO) rules
+	AlertSpreadWide   AlertType = "spread_wide"
+	AlertImbalanceBid AlertType = "imbalance_bid"
+	AlertImbalanceAsk AlertType = "imbalance_ask"
+	AlertMidUp        AlertType = "mid_up"
+	AlertMidDown      AlertType = "mid_down"
+
+	AlertVolumeSpike AlertType = "volume_spike"
+	AlertLargePrint  AlertType = "large_print"
+	AlertVWAPAbove   AlertType = "vwap_cross_above"
+	AlertVWAPBelow   AlertType = "vwap_cross_below"
+
+	// Moving averages on bars (fast vs slow, or bar close vs average)
+	AlertMACrossUp    AlertType = "ma_cross_up"
+	AlertMACrossDown  AlertType = "ma_cross_down"
+	AlertMAPriceAbove AlertType = "ma_price_above"
+	AlertMAPriceBelow AlertType = "ma_price_below"
+
+	AlertNewHigh AlertType = "new_high"
+	AlertNewLow  AlertType = "new_low"
+
+	AlertPullbackDown AlertType = "pullback_down" // off the high
+	AlertPullbackUp   AlertType = "pullback_up"   // off the low
+
+	AlertRangeUp   AlertType = "orb_up"   // opening range breakout
+	AlertRangeDown AlertType = "orb_down" // opening range breakdown
+
+	AlertRelativeUp   AlertType = "relative_up"
+	AlertRelativeDown AlertType = "relative_down"
+
+	// Pair spread vs its rolling mean (Symbol is the pair key, "MU/WDC")
+	AlertPairUp   AlertType = "pair_spread_up"
+	AlertPairDown AlertType = "pair_spread_down"
+
+	// Session transitions ("Regular session open.")
+	AlertSession AlertType = "session"
 )
 
 type Alert struct {
 	Type      AlertType
 	Symbol    string
 	Price     float64
+	Time      time.Time
 	Message   string
 	SpeakText string
 }
@@ -33,6 +72,13 @@ type Alert struct {
 type Config struct {
 	GlobalCooldown time.Duration
 	HistoryWindow  time.Duration
+	BarRetention   int // closed bars kept per symbol and bar size (ma_cross)
+
+	// Optional session model. With a calendar, rule sessions: lists apply and the
+	// base_change baseline resets when one of the ResetOn phases begins
+	// (around-the-clock markets reset at the start of each day instead).
+	Calendar *session.Calendar
+	ResetOn  []session.Phase
 }
 
 type Engine struct {
@@ -42,6 +88,13 @@ type Engine struct {
 
 	mu     sync.Mutex
 	state  map[string]*symbolState
+
+	// history benchmarks must keep for relative_strength windows, by ticker
+	benchKeep map[string]time.Duration
+
+	// pair rules by leg ticker (index into wl.Pairs), and their state by Pair.Key
+	pairsByLeg map[string][]int
+	pairs      map[string]*symbolState
 }
 
 type point struct {
@@ -55,11 +108,67 @@ type symbolState struct {
 	lastPrice float64
 	lastTime  time.Time
 
+	// session of the last price tick (calendar only)
+	phase     session.Phase
+	day       string
+	phaseTime time.Time
+
+	// base_change references other than the first tick
+	prevClose float64
+	openPrice float64 // official open of openDay, else its first regular-session price
+	openDay   string
+	vwapPV    float64 // session VWAP: sum(price*volume) since pre-market / regular open
+	vwapVol   float64
+	vwapFull  bool    // the session VWAP restarted at a session start the radar saw
+	dayVWAP   float64 // the feed's day VWAP, used until then
+
+	// session high/low since sessStart, and when each was set
+	high, low         float64
+	highTime, lowTime time.Time
+	sessStart         time.Time
+
+	// pullback: running high/low since the session start or the rule's last alert
+	pbHigh, pbLow float64
+
+	// opening_range: range of orDay (first tick orFrom), its volume, recent
+	// volume for confirmation and which sides have fired
+	orDay         string
+	orFrom        time.Time
+	orHigh, orLow float64
+	orVol         float64
+	orRecent      []point
+	orUp, orDown  bool
+
+	// vwap_cross: confirmed side of VWAP and a pending switch ("above" / "below")
+	vwapSide      string
+	vwapCand      string
+	vwapCandSince time.Time
+
 	hist []point
 
+	// aggregate volume (volume_spike), kept even when price rules use the midpoint
+	volHist   []point
+	volSince  time.Time // first aggregate seen (trailing baseline needs a full window)
+	avgVolume float64   // average daily volume (baseline: adv)
+
+	// resampled bars by bar length (ma_cross, volatility); retention is BarRetention, not HistoryWindow
+	bars map[time.Duration]*barSeries
+
+	// latest NBBO (quote subscription)
+	bid, ask         float64
+	bidSize, askSize float64
+	quoteTime        time.Time
+	midHist          []point
+
 	// for edge detection (avoid repeating while condition stays true)
 	active map[string]bool
 
+	// escalation of moves still triggered (base change, momentum), by key
+	esc map[string]escalation
+
+	// conditions waiting for confirm (not yet active), by key
+	pending map[string]pending
+
 	// cooldown by key
 	lastAlert map[string]time.Time
 }
@@ -71,135 +180,467 @@ func NewEngine(cfg Config, wl *watchlist.Watchlist, log zerolog.Logger) *Engine
 	if cfg.HistoryWindow <= 0 {
 		cfg.HistoryWindow = 5 * time.Minute
 	}
+	if cfg.BarRetention <= 0 {
+		cfg.BarRetention = 500
+	}
+	pairsByLeg := map[string][]int{}
+	pairs := map[string]*symbolState{}
+	for i := range wl.Pairs {
+		p := &wl.Pairs[i]
+		pairsByLeg[p.A] = append(pairsByLeg[p.A], i)
+		pairsByLeg[p.B] = append(pairsByLeg[p.B], i)
+		pairs[p.Key()] = &symbolState{active: map[string]bool{}, lastAlert: map[string]time.Time{}}
+	}
+	benchKeep := map[string]time.Duration{}
+	for _, ws := range wl.Symbols {
+		if r := ws.RelativeStrength; r != nil && r.Window > 0 {
+			if w := r.Window.ToDuration() + time.Minute; w > benchKeep[ws.Benchmark] {
+				benchKeep[ws.Benchmark] = w
+			}
+		}
+		if ws.OpeningRange != nil && (cfg.Calendar == nil || !hasRegularSession(&ws)) {
+			log.Warn().Str("symbol", ws.Ticker).Msg("opening_range needs a regular session (stocks/options with the session calendar); rule inactive")
+		}
+		if v := ws.Volatility; v != nil && v.Lookback+1 > cfg.BarRetention {
+			log.Warn().Str("symbol", ws.Ticker).Int("lookback", v.Lookback).Int("bar_retention", cfg.BarRetention).
+				Msg("volatility lookback needs more bars than radar.bar_retention keeps; sigma thresholds inactive")
+		}
+		for _, r := range ws.MACross {
+			if r.Slow+1 > cfg.BarRetention {
+				log.Warn().Str("symbol", ws.Ticker).Int("slow", r.Slow).Int("bar_retention", cfg.BarRetention).
+					Msg("ma_cross needs more bars than radar.bar_retention keeps; rule inactive")
+			}
+		}
+	}
 	return &Engine{
 		cfg:  cfg,
 		wl:   wl,
 		log:  log,
 		state: make(map[string]*symbolState),
+
+		benchKeep:  benchKeep,
+		pairsByLeg: pairsByLeg,
+		pairs:      pairs,
 	}
 }
 
-func (e *Engine) Update(symbol string, price float64, volume float64, ts time.Time) []Alert {
+// Agg is one aggregate window as the engine reads it.
+type Agg struct {
+	Price   float64 // close of the window
+	Volume  float64
+	VWAP    float64 // window VWAP, used for the session VWAP (0 = use Price)
+	DayVWAP float64 // today's VWAP from the feed (0 = not sent)
+	High    float64 // window high and low (0 = Price)
+	Low     float64
+	DayOpen float64 // today's official open (0 = not sent)
+	Time    time.Time
+}
+
+// Update feeds one aggregate (last price + window volume). vwap is the window VWAP
+// used for the session VWAP (0 = use price).
+func (e *Engine) Update(symbol string, price float64, volume float64, vwap float64, ts time.Time) []Alert {
+	return e.UpdateAgg(symbol, Agg{Price: price, Volume: volume, VWAP: vwap, Time: ts})
+}
+
+// UpdateAgg feeds one aggregate with the feed's day-level fields.
+// Symbols with price_source: mid ignore aggregates for price rules; see UpdateQuote.
+func (e *Engine) UpdateAgg(symbol string, a Agg) []Alert {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	ws := e.wl.Find(symbol)
+	ws, st := e.lookup(symbol)
 	if ws == nil {
 		return nil
 	}
-	if ws.Enabled != nil && !*ws.Enabled {
+	price, volume, vwap, ts := a.Price, a.Volume, a.VWAP, a.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	if price <= 0 {
 		return nil
 	}
 
+	phase := e.rollSession(ws, st, symbol, price, ts)
+	if a.DayOpen > 0 && (phase == session.Regular || phase == session.After) && hasRegularSession(ws) {
+		// the official open beats whichever regular-session tick the radar saw first
+		st.openDay, st.openPrice = st.day, a.DayOpen
+	}
+	if vwap <= 0 {
+		vwap = price
+	}
+	st.vwapPV += vwap * volume
+	st.vwapVol += volume
+	if a.DayVWAP > 0 {
+		st.dayVWAP = a.DayVWAP
+	}
+	if ws.OpeningRange != nil {
+		e.rangeVolume(ws, st, volume, ts)
+	}
+
+	var alerts []Alert
+	if ws.PriceSource != watchlist.PriceSourceMid {
+		// update history
+		st.hist = append(st.hist, point{t: ts, p: price, v: volume})
+		st.hist = pruneByAge(st.hist, ts.Add(-e.histKeep(ws)))
+
+		// the window's range, so the session high/low sees intrabar extremes
+		high, low := max(a.High, price), price
+		if a.Low > 0 {
+			low = min(a.Low, price)
+		}
+		alerts = e.evalPrice(ws, st, symbol, price, high, low, ts, phase)
+	}
+
+	if ws.VolumeSpike != nil {
+		alerts = append(alerts, e.evalVolume(ws, st, symbol, price, volume, ts, phase)...)
+	}
+	return alerts
+}
+
+// SetPrevClose sets the previous close used by base_change reference: prev_close.
+func (e *Engine) SetPrevClose(symbol string, price float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, st := e.lookup(symbol); st != nil && price > 0 {
+		st.prevClose = price
+	}
+}
+
+// SetAvgVolume sets the average daily volume used by volume_spike baseline: adv.
+func (e *Engine) SetAvgVolume(symbol string, volume float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, st := e.lookup(symbol); st != nil && volume > 0 {
+		st.avgVolume = volume
+	}
+}
+
+// lookup returns the watchlist entry and state for an enabled symbol (nil if not watched).
+func (e *Engine) lookup(symbol string) (*watchlist.Symbol, *symbolState) {
+	ws := e.wl.Find(symbol)
+	if ws == nil {
+		return nil, nil
+	}
+	if ws.Enabled != nil && !*ws.Enabled {
+		return nil, nil
+	}
+
 	st := e.state[symbol]
 	if st == nil {
 		st = &symbolState{
+			bars:      map[time.Duration]*barSeries{},
 			active:    map[string]bool{},
+			esc:       map[string]escalation{},
+			pending:   map[string]pending{},
 			lastAlert: map[string]time.Time{},
 		}
 		e.state[symbol] = st
 	}
-	if ts.IsZero() {
-		ts = time.Now()
-	}
-	if price <= 0 {
-		return nil
-	}
+	return ws, st
+}
 
-	// base set on first tick
+// evalPrice runs the price rules (base change, momentum, price levels, ...) against
+// the symbol's reference price (last trade, or midpoint for price_source: mid).
+// high and low are the range the price came from (an aggregate window), for the
+// session high/low. The caller has already appended the price to st.hist and rolled the session.
+func (e *Engine) evalPrice(ws *watchlist.Symbol, st *symbolState, symbol string, price, high, low float64, ts time.Time, phase session.Phase) []Alert {
+	// base set on first tick (of the process, or of the session after a reset)
 	if st.basePrice == 0 {
 		st.basePrice = price
 	}
+	if st.sessStart.IsZero() {
+		st.sessStart = ts
+	}
 
 	st.lastPrice = price
 	st.lastTime = ts
 
-	// update history
-	st.hist = append(st.hist, point{t: ts, p: price, v: volume})
-	st.hist = pruneByAge(st.hist, ts.Add(-e.cfg.HistoryWindow))
-
 	var alerts []Alert
+	spoken := ws.Spoken()
 
-	// --- Base change rule (relative to basePrice from first seen) ---
-	if ws.BaseChange != nil && st.basePrice > 0 {
+	var closed map[time.Duration]bool
+	if len(ws.MACross) > 0 || ws.Volatility != nil {
+		closed = e.feedBars(ws, st, price, ts)
+	}
+	vol, volOK := 0.0, false
+	if ws.Volatility != nil {
+		vol, volOK = e.volatility(ws, st)
+	}
+
+	// --- Base change rule (relative to the configured reference price) ---
+	if ws.BaseChange != nil {
 		upKey := "base_up"
 		downKey := "base_down"
-		pct := ((price - st.basePrice) / st.basePrice) * 100.0
-
-		if ws.BaseChange.UpPct > 0 {
-			isUp := pct >= ws.BaseChange.UpPct
-			alerts = append(alerts, e.edgeAlert(ws, st, upKey, isUp, ws.BaseChange.Cooldown.ToDuration(),
-				AlertBaseUp, symbol, price,
-				fmt.Sprintf("%s up %.2f%% vs baseline", symbol, pct),
-				fmt.Sprintf("Alert. %s up %.1f percent.", symbol, pct),
+		base, label, said := e.baseline(ws.BaseChange.Reference, ws.BaseChange.Price, st)
+		pct := 0.0
+		if base > 0 {
+			pct = ((price - base) / base) * 100.0
+		}
+		on := base > 0 && ws.BaseChange.Sessions.Allows(phase)
+		rearm := ws.BaseChange.RearmPct
+		// sigma thresholds: volatility scaled to the time since the session started
+		z := 0.0
+		if volOK {
+			z = sigmas(pct, vol, ts.Sub(st.sessStart), ws.Volatility.Bar.ToDuration())
+		}
+
+		if ws.BaseChange.UpPct > 0 || ws.BaseChange.UpSigma > 0 {
+			up := on && pct >= ws.BaseChange.UpPct && sigmaMet(ws.BaseChange.UpSigma, volOK, z)
+			hold := up || (on && rearm > 0 && pct > ws.BaseChange.UpPct-rearm)
+			isUp := e.trigger(st, upKey, up, hold, ws.BaseChange.Confirm, ts)
+			note, noteSaid := sigmaNote(ws, ws.BaseChange.UpSigma, z)
+			alerts = append(alerts, e.moveAlert(ws, st, upKey, isUp, pct, ws.BaseChange.Escalate, ws.BaseChange.Cooldown.ToDuration(),
+				AlertBaseUp, symbol, price, ts,
+				fmt.Sprintf("%s up %.2f%% %s%s", symbol, pct, label, note),
+				fmt.Sprintf("Alert. %s up %.1f percent%s%s.", spoken, pct, said, noteSaid),
+				fmt.Sprintf("Alert. %s now up %.1f percent%s.", spoken, pct, said),
 			)...)
 		}
-		if ws.BaseChange.DownPct > 0 {
-			isDown := pct <= -math.Abs(ws.BaseChange.DownPct)
-			alerts = append(alerts, e.edgeAlert(ws, st, downKey, isDown, ws.BaseChange.Cooldown.ToDuration(),
-				AlertBaseDown, symbol, price,
-				fmt.Sprintf("%s down %.2f%% vs baseline", symbol, math.Abs(pct)),
-				fmt.Sprintf("Alert. %s down %.1f percent.", symbol, math.Abs(pct)),
+		if ws.BaseChange.DownPct > 0 || ws.BaseChange.DownSigma > 0 {
+			down := on && pct <= -math.Abs(ws.BaseChange.DownPct) && sigmaMet(ws.BaseChange.DownSigma, volOK, -z)
+			hold := down || (on && rearm > 0 && pct < -math.Abs(ws.BaseChange.DownPct)+rearm)
+			isDown := e.trigger(st, downKey, down, hold, ws.BaseChange.Confirm, ts)
+			note, noteSaid := sigmaNote(ws, ws.BaseChange.DownSigma, -z)
+			alerts = append(alerts, e.moveAlert(ws, st, downKey, isDown, -pct, ws.BaseChange.Escalate, ws.BaseChange.Cooldown.ToDuration(),
+				AlertBaseDown, symbol, price, ts,
+				fmt.Sprintf("%s down %.2f%% %s%s", symbol, math.Abs(pct), label, note),
+				fmt.Sprintf("Alert. %s down %.1f percent%s%s.", spoken, math.Abs(pct), said, noteSaid),
+				fmt.Sprintf("Alert. %s now down %.1f percent%s.", spoken, math.Abs(pct), said),
 			)...)
 		}
 	}
 
-	// --- Momentum rule (relative to price N seconds ago) ---
-	if ws.Momentum != nil {
-		win := ws.Momentum.Window.ToDuration()
+	// --- Momentum rules (relative to price N seconds ago, one per window) ---
+	for _, m := range ws.Momentum {
+		win := m.Window.ToDuration()
 		if win <= 0 {
 			win = 60 * time.Second
 		}
 		oldPrice, ok := priceAtOrBefore(st.hist, ts.Add(-win))
-		if ok && oldPrice > 0 {
-			pct := ((price - oldPrice) / oldPrice) * 100.0
-			upKey := "mom_up_" + win.String()
-			downKey := "mom_down_" + win.String()
-
-			if ws.Momentum.UpPct > 0 {
-				isUp := pct >= ws.Momentum.UpPct
-				alerts = append(alerts, e.edgeAlert(ws, st, upKey, isUp, ws.Momentum.Cooldown.ToDuration(),
-					AlertMomentumUp, symbol, price,
-					fmt.Sprintf("%s momentum up %.2f%% in %s", symbol, pct, win),
-					fmt.Sprintf("Momentum. %s up %.1f percent in the last %d seconds.", symbol, pct, int(win.Seconds())),
-				)...)
-			}
-			if ws.Momentum.DownPct > 0 {
-				isDown := pct <= -math.Abs(ws.Momentum.DownPct)
-				alerts = append(alerts, e.edgeAlert(ws, st, downKey, isDown, ws.Momentum.Cooldown.ToDuration(),
-					AlertMomentumDown, symbol, price,
-					fmt.Sprintf("%s momentum down %.2f%% in %s", symbol, math.Abs(pct), win),
-					fmt.Sprintf("Momentum. %s down %.1f percent in the last %d seconds.", symbol, math.Abs(pct), int(win.Seconds())),
-				)...)
-			}
+		if !ok || oldPrice <= 0 {
+			continue
+		}
+		pct := ((price - oldPrice) / oldPrice) * 100.0
+		upKey := "mom_up_" + win.String()
+		downKey := "mom_down_" + win.String()
+		on := m.Sessions.Allows(phase)
+		z := 0.0
+		if volOK {
+			z = sigmas(pct, vol, win, ws.Volatility.Bar.ToDuration())
+		}
+
+		if m.UpPct > 0 || m.UpSigma > 0 {
+			up := on && pct >= m.UpPct && sigmaMet(m.UpSigma, volOK, z)
+			hold := up || (on && m.RearmPct > 0 && pct > m.UpPct-m.RearmPct)
+			isUp := e.trigger(st, upKey, up, hold, m.Confirm, ts)
+			note, noteSaid := sigmaNote(ws, m.UpSigma, z)
+			alerts = append(alerts, e.moveAlert(ws, st, upKey, isUp, pct, m.Escalate, m.Cooldown.ToDuration(),
+				AlertMomentumUp, symbol, price, ts,
+				fmt.Sprintf("%s momentum up %.2f%% in %s%s", symbol, pct, win, note),
+				fmt.Sprintf("Momentum. %s up %.1f percent in the last %s%s.", spoken, pct, spokenWindow(win), noteSaid),
+				fmt.Sprintf("Momentum. %s now up %.1f percent in the last %s.", spoken, pct, spokenWindow(win)),
+			)...)
+		}
+		if m.DownPct > 0 || m.DownSigma > 0 {
+			down := on && pct <= -math.Abs(m.DownPct) && sigmaMet(m.DownSigma, volOK, -z)
+			hold := down || (on && m.RearmPct > 0 && pct < -math.Abs(m.DownPct)+m.RearmPct)
+			isDown := e.trigger(st, downKey, down, hold, m.Confirm, ts)
+			note, noteSaid := sigmaNote(ws, m.DownSigma, -z)
+			alerts = append(alerts, e.moveAlert(ws, st, downKey, isDown, -pct, m.Escalate, m.Cooldown.ToDuration(),
+				AlertMomentumDown, symbol, price, ts,
+				fmt.Sprintf("%s momentum down %.2f%% in %s%s", symbol, math.Abs(pct), win, note),
+				fmt.Sprintf("Momentum. %s down %.1f percent in the last %s%s.", spoken, math.Abs(pct), spokenWindow(win), noteSaid),
+				fmt.Sprintf("Momentum. %s now down %.1f percent in the last %s.", spoken, math.Abs(pct), spokenWindow(win)),
+			)...)
 		}
 	}
 
-	// --- Price cross rule (absolute levels) ---
-	if ws.PriceCross != nil {
-		if ws.PriceCross.Above > 0 {
-			key := fmt.Sprintf("cross_above_%.4f", ws.PriceCross.Above)
-			isAbove := price >= ws.PriceCross.Above
-			alerts = append(alerts, e.edgeAlert(ws, st, key, isAbove, ws.PriceCross.Cooldown.ToDuration(),
-				AlertCrossAbove, symbol, price,
-				fmt.Sprintf("%s crossed above %.2f", symbol, ws.PriceCross.Above),
-				fmt.Sprintf("Price level. %s crossed above %.2f.", symbol, ws.PriceCross.Above),
+	// --- Price levels (absolute, optionally named) ---
+	for i := range ws.Levels {
+		l := &ws.Levels[i]
+		on := l.Sessions.Allows(phase)
+		name := fmt.Sprintf("%.2f", l.Price)
+		said := name
+		if l.Label != "" {
+			name = fmt.Sprintf("%s %.2f", l.Label, l.Price)
+			said = fmt.Sprintf("%s, %.2f", l.Label, l.Price)
+		}
+		if l.Above() {
+			key := fmt.Sprintf("cross_above_%.4f", l.Price)
+			above := on && price >= l.Price
+			hold := above || (on && l.RearmPct > 0 && price > l.Price*(1-l.RearmPct/100))
+			isAbove := e.trigger(st, key, above, hold, l.Confirm, ts)
+			alerts = append(alerts, e.edgeAlert(ws, st, key, isAbove, l.Cooldown.ToDuration(),
+				AlertCrossAbove, symbol, price, ts,
+				fmt.Sprintf("%s crossed above %s", symbol, name),
+				fmt.Sprintf("Price level. %s crossed above %s.", spoken, said),
 			)...)
 		}
-		if ws.PriceCross.Below > 0 {
-			key := fmt.Sprintf("cross_below_%.4f", ws.PriceCross.Below)
-			isBelow := price <= ws.PriceCross.Below
-			alerts = append(alerts, e.edgeAlert(ws, st, key, isBelow, ws.PriceCross.Cooldown.ToDuration(),
-				AlertCrossBelow, symbol, price,
-				fmt.Sprintf("%s crossed below %.2f", symbol, ws.PriceCross.Below),
-				fmt.Sprintf("Price level. %s crossed below %.2f.", symbol, ws.PriceCross.Below),
+		if l.Below() {
+			key := fmt.Sprintf("cross_below_%.4f", l.Price)
+			below := on && price <= l.Price
+			hold := below || (on && l.RearmPct > 0 && price < l.Price*(1+l.RearmPct/100))
+			isBelow := e.trigger(st, key, below, hold, l.Confirm, ts)
+			alerts = append(alerts, e.edgeAlert(ws, st, key, isBelow, l.Cooldown.ToDuration(),
+				AlertCrossBelow, symbol, price, ts,
+				fmt.Sprintf("%s crossed below %s", symbol, name),
+				fmt.Sprintf("Price level. %s crossed below %s.", spoken, said),
 			)...)
 		}
 	}
 
+	if ws.VWAPCross != nil {
+		alerts = append(alerts, e.evalVWAPCross(ws, st, symbol, price, ts, phase)...)
+	}
+	if len(ws.MACross) > 0 {
+		alerts = append(alerts, e.evalMA(ws, st, symbol, price, ts, phase, closed)...)
+	}
+	if ws.RelativeStrength != nil {
+		alerts = append(alerts, e.evalRelative(ws, st, symbol, price, ts, phase)...)
+	}
+	alerts = append(alerts, e.evalExtremes(ws, st, symbol, high, low, ts, phase)...)
+	if ws.Pullback != nil {
+		alerts = append(alerts, e.evalPullback(ws, st, symbol, price, ts, phase)...)
+	}
+	if ws.OpeningRange != nil {
+		alerts = append(alerts, e.evalOpeningRange(ws, st, symbol, price, ts, phase)...)
+	}
+	if len(e.pairsByLeg[symbol]) > 0 {
+		alerts = append(alerts, e.evalPairs(symbol, ts, phase)...)
+	}
+
 	return alerts
 }
 
+// histKeep is how much price history a symbol needs: HistoryWindow, or more
+// when a momentum or relative_strength window (its own, or of a symbol using it
+// as benchmark) is longer.
+func (e *Engine) histKeep(ws *watchlist.Symbol) time.Duration {
+	keep := e.cfg.HistoryWindow
+	for _, m := range ws.Momentum {
+		if w := m.Window.ToDuration() + time.Minute; w > keep {
+			keep = w
+		}
+	}
+	if r := ws.RelativeStrength; r != nil {
+		if w := r.Window.ToDuration() + time.Minute; w > keep {
+			keep = w
+		}
+	}
+	if w := e.benchKeep[ws.Ticker]; w > keep {
+		keep = w
+	}
+	return keep
+}
+
+// spokenWindow renders a momentum window for TTS: "60 seconds", "5 minutes".
+func spokenWindow(win time.Duration) string {
+	if win < 2*time.Minute {
+		return fmt.Sprintf("%d seconds", int(win.Seconds()))
+	}
+	return spokenDuration(win)
+}
+
+// phaseAt is the session phase at ts ("" without a calendar; every rule allows "").
+func (e *Engine) phaseAt(ts time.Time) session.Phase {
+	if e.cfg.Calendar == nil {
+		return ""
+	}
+	return e.cfg.Calendar.Phase(ts)
+}
+
+// baseline returns the reference price (0 = not known yet) for a base_change
+// reference, a label for the message and a suffix for the spoken text.
+func (e *Engine) baseline(ref string, fixed float64, st *symbolState) (float64, string, string) {
+	switch ref {
+	case watchlist.ReferencePrevClose:
+		return st.prevClose, fmt.Sprintf("vs prev close %.2f", st.prevClose), " on the day"
+	case watchlist.ReferenceSessionOpen:
+		if e.cfg.Calendar == nil {
+			return st.basePrice, "vs baseline", ""
+		}
+		if st.openDay != st.day {
+			return 0, "", "" // today's open not seen
+		}
+		return st.openPrice, fmt.Sprintf("vs open %.2f", st.openPrice), " from the open"
+	case watchlist.ReferenceVWAP:
+		vwap := st.sessionVWAP()
+		return vwap, fmt.Sprintf("vs VWAP %.2f", vwap), " versus VWAP"
+	case watchlist.ReferenceFixed:
+		return fixed, fmt.Sprintf("vs %.2f", fixed), fmt.Sprintf(" from %.2f", fixed)
+	default:
+		return st.basePrice, "vs baseline", ""
+	}
+}
+
+// rollSession records the session of this tick and clears the baseline when a
+// ResetOn phase begins (or, for crypto/forex, when the day changes). It restarts
+// the session VWAP and high/low when pre-market or the regular session opens
+// (crypto/forex: daily) and takes the open from a tick in the regular session's first minute.
+func (e *Engine) rollSession(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time) session.Phase {
+	if e.cfg.Calendar == nil {
+		return ""
+	}
+	if ts.Before(st.phaseTime) {
+		// late tick from another stream (agg vs quote); don't flip back across a boundary
+		return st.phase
+	}
+	phase := e.cfg.Calendar.Phase(ts)
+	day := e.cfg.Calendar.Day(ts)
+	prevPhase, prevDay := st.phase, st.day
+	st.phase, st.day, st.phaseTime = phase, day, ts
+
+	allDay := ws.AroundTheClock() || ws.Market == watchlist.MarketForex
+	if st.openDay != day {
+		// a regular-session tick stands in for the open only if it came right at the
+		// open; otherwise wait for the feed's official open (UpdateAgg)
+		if allDay || (phase == session.Regular && ts.Sub(e.cfg.Calendar.OpenAt(ts)) <= time.Minute) {
+			st.openDay = day
+			st.openPrice = price
+		}
+	}
+
+	if prevDay == "" {
+		return phase
+	}
+
+	newDay := day != prevDay
+	newSession := newDay
+	if !allDay {
+		newSession = (newDay || phase != prevPhase) && (phase == session.Pre || phase == session.Regular)
+	}
+	if newSession {
+		st.vwapPV, st.vwapVol, st.vwapFull = 0, 0, true
+		st.vwapSide, st.vwapCand = "", ""
+		st.high, st.low = 0, 0
+		st.sessStart = ts
+		st.pbHigh, st.pbLow = 0, 0
+	}
+
+	if len(e.cfg.ResetOn) == 0 {
+		return phase
+	}
+
+	reset := false
+	if allDay {
+		reset = newDay
+	} else if phase != prevPhase || newDay {
+		for _, p := range e.cfg.ResetOn {
+			if p == phase {
+				reset = true
+				break
+			}
+		}
+	}
+	if reset {
+		e.log.Debug().Str("symbol", symbol).Str("session", string(phase)).Float64("old_base", st.basePrice).Msg("session boundary: baseline reset")
+		st.basePrice = 0
+	}
+	return phase
+}
+
 func (e *Engine) edgeAlert(
 	ws *watchlist.Symbol,
 	st *symbolState,
@@ -209,19 +650,10 @@ func (e *Engine) edgeAlert(
 	atype AlertType,
 	symbol string,
 	price float64,
+	ts time.Time,
 	message string,
 	speak string,
 ) []Alert {
-	if cooldown <= 0 {
-		if ws.Cooldown.ToDuration() > 0 {
-			cooldown = ws.Cooldown.ToDuration()
-		} else {
-			cooldown = e.cfg.GlobalCooldown
-		}
-	}
-
-	now := time.Now()
-
 	// edge detection: only fire when condition becomes true
 	prev := st.active[key]
 	st.active[key] = condition
@@ -229,6 +661,34 @@ func (e *Engine) edgeAlert(
 	if !condition || prev {
 		return nil
 	}
+	return e.cooldownAlert(ws, st, key, cooldown, atype, symbol, price, ts, message, speak)
+}
+
+// cooldownAlert fires unless key alerted within the cooldown (the rule's, else the
+// symbol's, else the global one; ws is nil for rules no symbol owns). edgeAlert
+// calls it once a condition turns true; rules about discrete events call it directly.
+func (e *Engine) cooldownAlert(
+	ws *watchlist.Symbol,
+	st *symbolState,
+	key string,
+	cooldown time.Duration,
+	atype AlertType,
+	symbol string,
+	price float64,
+	ts time.Time,
+	message string,
+	speak string,
+) []Alert {
+	if cooldown <= 0 {
+		if ws != nil && ws.Cooldown.ToDuration() > 0 {
+			cooldown = ws.Cooldown.ToDuration()
+		} else {
+			cooldown = e.cfg.GlobalCooldown
+		}
+	}
+
+	// tick time (not wall time) so cooldowns behave the same under replay
+	now := ts
 
 	// cooldown
 	if last, ok := st.lastAlert[key]; ok {
@@ -242,6 +702,7 @@ func (e *Engine) edgeAlert(
 		Type:      atype,
 		Symbol:    symbol,
 		Price:     price,
+		Time:      ts,
 		Message:   message,
 		SpeakText: speak,
 	}}
@@ -0,0 +1,75 @@
+package radar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+func TestSessionOpenAfterLateStart(t *testing.T) {
+	newEngine := func() *Engine {
+		wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+			Ticker:     "MU",
+			BaseChange: &watchlist.BaseChangeRule{UpPct: 1, Reference: watchlist.ReferenceSessionOpen},
+		}}}
+		wl.Normalize()
+		return NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	}
+	start := time.Date(2026, 10, 15, 14, 0, 0, 0, session.NewYork()) // started mid-day
+
+	// the first tick seen is not the open: no reference, no alert
+	e := newEngine()
+	e.Update("MU", 100, 100, 0, start)
+	if got := e.Update("MU", 101.5, 100, 0, start.Add(10*time.Second)); len(got) != 0 {
+		t.Errorf("without the official open: %d alerts, want 0", len(got))
+	}
+
+	// the official open from the feed is the reference
+	e = newEngine()
+	e.UpdateAgg("MU", Agg{Price: 100, Volume: 100, DayOpen: 101, Time: start})
+	got := e.UpdateAgg("MU", Agg{Price: 102.5, Volume: 100, DayOpen: 101, Time: start.Add(10 * time.Second)})
+	if len(got) != 1 || got[0].Type != AlertBaseUp {
+		t.Fatalf("with the official open: %+v, want one %s", got, AlertBaseUp)
+	}
+	if want := "MU up 1.49% vs open 101.00"; got[0].Message != want {
+		t.Errorf("message = %q, want %q", got[0].Message, want)
+	}
+}
+
+func TestSessionOpenFromFirstMinute(t *testing.T) {
+	wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+		Ticker:     "MU",
+		BaseChange: &watchlist.BaseChangeRule{UpPct: 1, Reference: watchlist.ReferenceSessionOpen},
+	}}}
+	wl.Normalize()
+	e := NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	open := time.Date(2026, 10, 15, 9, 30, 5, 0, session.NewYork())
+
+	e.Update("MU", 100, 100, 0, open)
+	if got := e.Update("MU", 101.5, 100, 0, open.Add(time.Minute)); len(got) != 1 {
+		t.Errorf("%d alerts from a tick at the open, want 1", len(got))
+	}
+}
+
+func TestSessionVWAPAfterLateStart(t *testing.T) {
+	wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+		Ticker:     "MU",
+		BaseChange: &watchlist.BaseChangeRule{UpPct: 1, Reference: watchlist.ReferenceVWAP},
+	}}}
+	wl.Normalize()
+	e := NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	start := time.Date(2026, 10, 15, 14, 0, 0, 0, session.NewYork())
+
+	// the radar's own VWAP would be near 99.9; the feed's day VWAP says the day traded lower
+	if got := e.UpdateAgg("MU", Agg{Price: 99.5, Volume: 1000, DayVWAP: 99, Time: start}); len(got) != 0 {
+		t.Fatalf("first tick: %+v, want no alert", got)
+	}
+	got := e.UpdateAgg("MU", Agg{Price: 100.2, Volume: 1000, DayVWAP: 99, Time: start.Add(time.Second)})
+	if len(got) != 1 || got[0].Message != "MU up 1.21% vs VWAP 99.00" {
+		t.Errorf("alerts = %+v, want one measured from the day VWAP 99", got)
+	}
+}
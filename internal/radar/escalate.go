@@ -0,0 +1,64 @@
+package radar
+
+import (
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// escalation is the progress of a move: its size (percent) at the last alert and
+// how many re-alerts it has had.
+type escalation struct {
+	level float64
+	tiers int
+}
+
+// moveAlert is edgeAlert for rules that measure a move (base change, momentum).
+// While the condition stays true, a move that extends to the rule's next
+// escalation tier is announced again with escSpeak, regardless of the cooldown.
+// Tiers count from the last alert, so a move that retraces and re-triggers within
+// the cooldown only speaks once it gets beyond what was already said.
+func (e *Engine) moveAlert(
+	ws *watchlist.Symbol,
+	st *symbolState,
+	key string,
+	condition bool,
+	move float64,
+	esc *watchlist.Escalation,
+	cooldown time.Duration,
+	atype AlertType,
+	symbol string,
+	price float64,
+	ts time.Time,
+	message string,
+	speak string,
+	escSpeak string,
+) []Alert {
+	prev := st.active[key]
+	alerts := e.edgeAlert(ws, st, key, condition, cooldown, atype, symbol, price, ts, message, speak)
+
+	_, known := st.esc[key]
+	switch {
+	case !condition:
+	case !prev:
+		if len(alerts) > 0 || !known {
+			st.esc[key] = escalation{level: move}
+		}
+	case esc != nil:
+		x := st.esc[key]
+		if move < esc.Next(x.level) || (esc.Max > 0 && x.tiers >= esc.Max) {
+			break
+		}
+		st.esc[key] = escalation{level: move, tiers: x.tiers + 1}
+		st.lastAlert[key] = ts
+		alerts = append(alerts, Alert{
+			Type:      atype,
+			Symbol:    symbol,
+			Price:     price,
+			Time:      ts,
+			Message:   message + " (extending)",
+			SpeakText: escSpeak,
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,55 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// evalExtremes tracks the session high and low from each update's range (an
+// aggregate's high and low) and runs the new_high_low rule. A break is announced
+// only when the previous extreme had stood for MinAge and the new one clears it by
+// MinPct, so a steady grind higher stays quiet.
+func (e *Engine) evalExtremes(ws *watchlist.Symbol, st *symbolState, symbol string, high, low float64, ts time.Time, phase session.Phase) []Alert {
+	prevHigh, prevHighTime := st.high, st.highTime
+	prevLow, prevLowTime := st.low, st.lowTime
+	if st.high == 0 || high > st.high {
+		st.high, st.highTime = high, ts
+	}
+	if st.low == 0 || low < st.low {
+		st.low, st.lowTime = low, ts
+	}
+
+	r := ws.NewHighLow
+	if r == nil {
+		return nil
+	}
+	on := r.Sessions.Allows(phase) && ts.Sub(st.sessStart) >= r.Warmup.ToDuration()
+	minAge := r.MinAge.ToDuration()
+	newHigh := on && prevHigh > 0 && ts.Sub(prevHighTime) >= minAge && high >= prevHigh*(1+r.MinPct/100) && high > prevHigh
+	newLow := on && prevLow > 0 && ts.Sub(prevLowTime) >= minAge && low <= prevLow*(1-r.MinPct/100) && low < prevLow
+
+	pre := phase == session.Pre
+	spoken := ws.Spoken()
+	alerts := e.edgeAlert(ws, st, "session_high", newHigh, r.Cooldown.ToDuration(),
+		AlertNewHigh, symbol, high, ts,
+		fmt.Sprintf("%s new session high %.2f (prev %.2f)", symbol, high, prevHigh),
+		spokenExtreme(spoken, "high", pre, high),
+	)
+	alerts = append(alerts, e.edgeAlert(ws, st, "session_low", newLow, r.Cooldown.ToDuration(),
+		AlertNewLow, symbol, low, ts,
+		fmt.Sprintf("%s new session low %.2f (prev %.2f)", symbol, low, prevLow),
+		spokenExtreme(spoken, "low", pre, low),
+	)...)
+	return alerts
+}
+
+// spokenExtreme reads "MU new high of day, 101.25." or "MU new pre-market low, 98.4."
+func spokenExtreme(spoken, kind string, pre bool, price float64) string {
+	if pre {
+		return fmt.Sprintf("%s new pre-market %s, %s.", spoken, kind, spokenPrice(price))
+	}
+	return fmt.Sprintf("%s new %s of day, %s.", spoken, kind, spokenPrice(price))
+}
@@ -0,0 +1,33 @@
+package radar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+func TestSessionHighFromAggHigh(t *testing.T) {
+	wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+		Ticker:     "MU",
+		NewHighLow: &watchlist.NewHighLowRule{},
+	}}}
+	wl.Normalize()
+	e := NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	ts := time.Date(2026, 10, 15, 10, 0, 0, 0, session.NewYork())
+
+	// closes at 100, but each window spiked to 100.5 and dipped to 99.5
+	for i := 0; i < 6*6; i++ {
+		if got := e.UpdateAgg("MU", Agg{Price: 100, High: 100.5, Low: 99.5, Volume: 100, Time: ts}); len(got) != 0 {
+			t.Fatalf("warmup: %+v", got)
+		}
+		ts = ts.Add(10 * time.Second)
+	}
+	got := e.UpdateAgg("MU", Agg{Price: 100.3, High: 101, Low: 100, Volume: 100, Time: ts})
+	if len(got) != 1 || got[0].Message != "MU new session high 101.00 (prev 100.50)" {
+		t.Errorf("alerts = %+v, want a new high of 101 over 100.50", got)
+	}
+}
@@ -0,0 +1,66 @@
+package radar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// evalMA checks the moving average rules whose bar series just closed a bar
+// (closed, from feedBars) on the closed bars.
+func (e *Engine) evalMA(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time, phase session.Phase, closed map[time.Duration]bool) []Alert {
+	var alerts []Alert
+	spoken := ws.Spoken()
+	for _, r := range ws.MACross {
+		d := r.Bar.ToDuration()
+		if !closed[d] {
+			continue
+		}
+		bars := st.bars[d].closed
+		n := len(bars)
+		slow, ok1 := movingAverage(r.Type, bars, r.Slow, n)
+		slowPrev, ok2 := movingAverage(r.Type, bars, r.Slow, n-1)
+		if !ok1 || !ok2 {
+			continue // not enough bars yet
+		}
+		fast, fastPrev := bars[n-1].close, bars[n-2].close
+		if r.Fast > 0 {
+			fast, _ = movingAverage(r.Type, bars, r.Fast, n)
+			fastPrev, _ = movingAverage(r.Type, bars, r.Fast, n-1)
+		}
+		up := fastPrev <= slowPrev && fast > slow
+		down := fastPrev >= slowPrev && fast < slow
+		on := r.Sessions.Allows(phase)
+
+		typ := strings.ToUpper(r.Type)
+		short, barName := barLabel(d)
+		key := fmt.Sprintf("ma_%s_%d_%d_%s", r.Type, r.Fast, r.Slow, short)
+		if r.Fast > 0 {
+			alerts = append(alerts, e.edgeAlert(ws, st, key+"_up", on && up, r.Cooldown.ToDuration(),
+				AlertMACrossUp, symbol, price, ts,
+				fmt.Sprintf("%s %s%d crossed above %s%d (%.2f / %.2f, %s bars)", symbol, typ, r.Fast, typ, r.Slow, fast, slow, short),
+				fmt.Sprintf("Trend. %s %d %s crossed above the %d %s on %s bars.", spoken, r.Fast, typ, r.Slow, typ, barName),
+			)...)
+			alerts = append(alerts, e.edgeAlert(ws, st, key+"_down", on && down, r.Cooldown.ToDuration(),
+				AlertMACrossDown, symbol, price, ts,
+				fmt.Sprintf("%s %s%d crossed below %s%d (%.2f / %.2f, %s bars)", symbol, typ, r.Fast, typ, r.Slow, fast, slow, short),
+				fmt.Sprintf("Trend. %s %d %s crossed below the %d %s on %s bars.", spoken, r.Fast, typ, r.Slow, typ, barName),
+			)...)
+			continue
+		}
+		alerts = append(alerts, e.edgeAlert(ws, st, key+"_up", on && up, r.Cooldown.ToDuration(),
+			AlertMAPriceAbove, symbol, price, ts,
+			fmt.Sprintf("%s closed above %s%d %.2f (%s bars)", symbol, typ, r.Slow, slow, short),
+			fmt.Sprintf("Trend. %s crossed above the %d %s on %s bars.", spoken, r.Slow, typ, barName),
+		)...)
+		alerts = append(alerts, e.edgeAlert(ws, st, key+"_down", on && down, r.Cooldown.ToDuration(),
+			AlertMAPriceBelow, symbol, price, ts,
+			fmt.Sprintf("%s closed below %s%d %.2f (%s bars)", symbol, typ, r.Slow, slow, short),
+			fmt.Sprintf("Trend. %s crossed below the %d %s on %s bars.", spoken, r.Slow, typ, barName),
+		)...)
+	}
+	return alerts
+}
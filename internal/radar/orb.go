@@ -0,0 +1,118 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// hasRegularSession reports whether the symbol trades a regular session with an
+// open (not crypto or forex).
+func hasRegularSession(ws *watchlist.Symbol) bool {
+	return !ws.AroundTheClock() && ws.Market != watchlist.MarketForex
+}
+
+// rollRange starts a new opening range on the first tick of a trading day.
+func (st *symbolState) rollRange(day string) {
+	if st.orDay == day {
+		return
+	}
+	st.orDay = day
+	st.orFrom = time.Time{}
+	st.orHigh, st.orLow, st.orVol = 0, 0, 0
+	st.orUp, st.orDown = false, false
+	// yesterday's breakout leaves its edge flag set; each day starts armed
+	for _, k := range []string{"orb_up", "orb_down"} {
+		delete(st.active, k)
+		delete(st.pending, k)
+	}
+}
+
+// rangeVolume adds an aggregate's volume to the opening range (while it forms) and
+// to the recent volume used to confirm breakouts.
+func (e *Engine) rangeVolume(ws *watchlist.Symbol, st *symbolState, volume float64, ts time.Time) {
+	cal := e.cfg.Calendar
+	if cal == nil || !hasRegularSession(ws) {
+		return
+	}
+	r := ws.OpeningRange
+	st.rollRange(cal.Day(ts))
+	open := cal.OpenAt(ts)
+	if !ts.Before(open) && ts.Before(open.Add(r.Window.ToDuration())) {
+		st.orVol += volume
+	}
+	st.orRecent = append(st.orRecent, point{t: ts, v: volume})
+	st.orRecent = pruneByAge(st.orRecent, ts.Add(-r.VolumeWindow.ToDuration()))
+}
+
+// evalOpeningRange builds the regular session's opening range from the prices of
+// its first Window and announces the first break of each side. A range only counts
+// if the radar saw it from the start (first tick within a minute of the open).
+func (e *Engine) evalOpeningRange(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time, phase session.Phase) []Alert {
+	cal := e.cfg.Calendar
+	if cal == nil || phase != session.Regular || !hasRegularSession(ws) {
+		return nil
+	}
+	r := ws.OpeningRange
+	st.rollRange(cal.Day(ts))
+
+	win := r.Window.ToDuration()
+	open := cal.OpenAt(ts)
+	if ts.Before(open.Add(win)) {
+		if st.orFrom.IsZero() {
+			st.orFrom = ts
+			st.orHigh, st.orLow = price, price
+		}
+		st.orHigh = max(st.orHigh, price)
+		st.orLow = min(st.orLow, price)
+		return nil
+	}
+	if st.orFrom.IsZero() || st.orFrom.Sub(open) > time.Minute {
+		return nil // range not seen from the open
+	}
+
+	volOK, ratio := true, 0.0
+	if r.VolumeMultiplier > 0 {
+		var recent float64
+		for _, p := range st.orRecent {
+			recent += p.v
+		}
+		normal := st.orVol * float64(r.VolumeWindow.ToDuration()) / float64(win)
+		if normal > 0 {
+			ratio = recent / normal
+		}
+		volOK = ratio >= r.VolumeMultiplier
+	}
+
+	short, name := barLabel(win)
+	rng := fmt.Sprintf("%s opening range %.2f-%.2f", short, st.orLow, st.orHigh)
+	if ratio > 0 {
+		rng += fmt.Sprintf(" (volume %.1fx)", ratio)
+	}
+	spoken := ws.Spoken()
+
+	var alerts []Alert
+	if !st.orUp {
+		up := volOK && price > st.orHigh*(1+r.BufferPct/100)
+		a := e.edgeAlert(ws, st, "orb_up", up, 0,
+			AlertRangeUp, symbol, price, ts,
+			fmt.Sprintf("%s broke above %s at %.2f", symbol, rng, price),
+			fmt.Sprintf("Breakout. %s above the %s opening range, %s.", spoken, name, spokenPrice(price)),
+		)
+		st.orUp = len(a) > 0
+		alerts = append(alerts, a...)
+	}
+	if !st.orDown {
+		down := volOK && price < st.orLow*(1-r.BufferPct/100)
+		a := e.edgeAlert(ws, st, "orb_down", down, 0,
+			AlertRangeDown, symbol, price, ts,
+			fmt.Sprintf("%s broke below %s at %.2f", symbol, rng, price),
+			fmt.Sprintf("Breakdown. %s below the %s opening range, %s.", spoken, name, spokenPrice(price)),
+		)
+		st.orDown = len(a) > 0
+		alerts = append(alerts, a...)
+	}
+	return alerts
+}
@@ -0,0 +1,45 @@
+package radar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+func TestOpeningRangeFiresEachSession(t *testing.T) {
+	wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+		Ticker:       "MU",
+		OpeningRange: &watchlist.OpeningRangeRule{},
+	}}}
+	wl.Normalize()
+	e := NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	ny := session.NewYork()
+
+	for _, day := range []int{15, 16} { // Thursday, Friday
+		ts := time.Date(2026, 10, day, 9, 30, 0, 0, ny)
+		var fired []Alert
+		// range 100.0-100.2 over the first 15 minutes, then a break to 101
+		for i := 0; i < 15*6; i++ {
+			fired = append(fired, e.Update("MU", 100+float64(i%3)*0.1, 100, 0, ts)...)
+			ts = ts.Add(10 * time.Second)
+		}
+		for i := 0; i < 6; i++ {
+			fired = append(fired, e.Update("MU", 101, 100, 0, ts)...)
+			ts = ts.Add(10 * time.Second)
+		}
+
+		n := 0
+		for _, a := range fired {
+			if a.Type == AlertRangeUp {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Fatalf("day %d: %d opening range breakouts, want 1 (alerts %+v)", day, n, fired)
+		}
+	}
+}
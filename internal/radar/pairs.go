@@ -0,0 +1,101 @@
+package radar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// minPairSamples is how many spread samples a pair needs before it is scored.
+const minPairSamples = 20
+
+// evalPairs runs the pair rules with symbol as a leg, using both legs' latest
+// prices. Each pair keeps its own sampled spread history for the rolling mean and
+// standard deviation; scoring starts once half a window of samples exists.
+func (e *Engine) evalPairs(symbol string, ts time.Time, phase session.Phase) []Alert {
+	var alerts []Alert
+	for _, i := range e.pairsByLeg[symbol] {
+		p := &e.wl.Pairs[i]
+		a, b := e.state[p.A], e.state[p.B]
+		if a == nil || b == nil || a.lastPrice <= 0 || b.lastPrice <= 0 {
+			continue
+		}
+		if ts.Sub(a.lastTime) > peerStale || ts.Sub(b.lastTime) > peerStale {
+			continue
+		}
+
+		x := a.lastPrice / b.lastPrice
+		if p.Formula == watchlist.PairSpread {
+			x = a.lastPrice - p.Hedge*b.lastPrice
+		}
+		win := p.Window.ToDuration()
+		st := e.pairs[p.Key()]
+		st.hist = appendSampled(st.hist, point{t: ts, p: x}, p.Sample.ToDuration())
+		st.hist = pruneByAge(st.hist, ts.Add(-win))
+		if len(st.hist) < minPairSamples || ts.Sub(st.hist[0].t) < win/2 {
+			continue
+		}
+
+		mean, sd := meanStd(st.hist)
+		z := 0.0
+		if sd > 0 {
+			z = (x - mean) / sd
+		}
+		dev := 0.0
+		if mean != 0 {
+			dev = (x - mean) / math.Abs(mean) * 100
+		}
+		byZ := p.ZScore > 0 && math.Abs(z) >= p.ZScore
+		byPct := p.Pct > 0 && math.Abs(dev) >= p.Pct
+		on := p.Sessions.Allows(phase) && (byZ || byPct)
+
+		name := p.Name
+		if name == "" {
+			name = e.spokenTicker(p.A) + " versus " + e.spokenTicker(p.B)
+		}
+		size := fmt.Sprintf("%s sigma", spokenRatio(math.Abs(z)))
+		if !byZ {
+			size = fmt.Sprintf("%.1f percent", math.Abs(dev))
+		}
+		msg := fmt.Sprintf("%s %s %.4f z %+.2f vs %s mean %.4f (%+.2f%%)", p.Key(), p.Formula, x, z, win, mean, dev)
+
+		// a pair belongs to neither leg: without its own cooldown it uses the global one
+		cooldown := p.Cooldown.ToDuration()
+		if cooldown <= 0 {
+			cooldown = e.cfg.GlobalCooldown
+		}
+		alerts = append(alerts, e.edgeAlert(nil, st, "pair_up", on && x > mean, cooldown,
+			AlertPairUp, p.Key(), x, ts, msg,
+			fmt.Sprintf("Pair. %s spread widened %s.", name, size),
+		)...)
+		alerts = append(alerts, e.edgeAlert(nil, st, "pair_down", on && x < mean, cooldown,
+			AlertPairDown, p.Key(), x, ts, msg,
+			fmt.Sprintf("Pair. %s spread narrowed %s.", name, size),
+		)...)
+	}
+	return alerts
+}
+
+// meanStd is the mean and population standard deviation of the history prices.
+func meanStd(h []point) (float64, float64) {
+	var sum, sq float64
+	for _, p := range h {
+		sum += p.p
+	}
+	mean := sum / float64(len(h))
+	for _, p := range h {
+		sq += (p.p - mean) * (p.p - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(h)))
+}
+
+// spokenTicker is how a watchlist ticker is read aloud.
+func (e *Engine) spokenTicker(ticker string) string {
+	if ws := e.wl.Find(ticker); ws != nil {
+		return ws.Spoken()
+	}
+	return ticker
+}
@@ -0,0 +1,51 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// evalPullback runs the pullback rule: the drop from the running high and the
+// rally from the running low. With since: alert the side that fired restarts its
+// high (low) at the current price, so the next alert needs a fresh move.
+func (e *Engine) evalPullback(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time, phase session.Phase) []Alert {
+	r := ws.Pullback
+	if st.pbHigh == 0 || price > st.pbHigh {
+		st.pbHigh = price
+	}
+	if st.pbLow == 0 || price < st.pbLow {
+		st.pbLow = price
+	}
+	off := (st.pbHigh - price) / st.pbHigh * 100
+	up := (price - st.pbLow) / st.pbLow * 100
+	on := r.Sessions.Allows(phase)
+	spoken := ws.Spoken()
+
+	var alerts []Alert
+	if r.DownPct > 0 {
+		a := e.edgeAlert(ws, st, "pullback_down", on && off >= r.DownPct, r.Cooldown.ToDuration(),
+			AlertPullbackDown, symbol, price, ts,
+			fmt.Sprintf("%s down %.2f%% from high %.2f", symbol, off, st.pbHigh),
+			fmt.Sprintf("Reversal. %s down %.1f percent from its high.", spoken, off),
+		)
+		if len(a) > 0 && r.Since == watchlist.SinceAlert {
+			st.pbHigh = price
+		}
+		alerts = append(alerts, a...)
+	}
+	if r.UpPct > 0 {
+		a := e.edgeAlert(ws, st, "pullback_up", on && up >= r.UpPct, r.Cooldown.ToDuration(),
+			AlertPullbackUp, symbol, price, ts,
+			fmt.Sprintf("%s up %.2f%% from low %.2f", symbol, up, st.pbLow),
+			fmt.Sprintf("Reversal. %s up %.1f percent from its low.", spoken, up),
+		)
+		if len(a) > 0 && r.Since == watchlist.SinceAlert {
+			st.pbLow = price
+		}
+		alerts = append(alerts, a...)
+	}
+	return alerts
+}
@@ -0,0 +1,128 @@
+package radar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// UpdateQuote feeds one NBBO update. It evaluates the symbol's quote rules and,
+// for price_source: mid, runs the price rules against the midpoint.
+func (e *Engine) UpdateQuote(symbol string, bid, ask, bidSize, askSize float64, ts time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ws, st := e.lookup(symbol)
+	if ws == nil {
+		return nil
+	}
+	// one-sided or crossed books carry no usable midpoint
+	if bid <= 0 || ask <= 0 || ask < bid {
+		return nil
+	}
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	mid := (bid + ask) / 2
+	st.bid, st.ask = bid, ask
+	st.bidSize, st.askSize = bidSize, askSize
+	st.quoteTime = ts
+
+	var alerts []Alert
+
+	if ws.PriceSource == watchlist.PriceSourceMid {
+		phase := e.rollSession(ws, st, symbol, mid, ts)
+		st.hist = appendSampled(st.hist, point{t: ts, p: mid}, time.Second)
+		st.hist = pruneByAge(st.hist, ts.Add(-e.histKeep(ws)))
+		alerts = append(alerts, e.evalPrice(ws, st, symbol, mid, mid, mid, ts, phase)...)
+	}
+
+	q := ws.Quote
+	if q == nil {
+		return alerts
+	}
+	spoken := ws.Spoken()
+	phase := e.phaseAt(ts)
+	on := q.Sessions.Allows(phase)
+
+	// --- Spread blowout (bps of midpoint) ---
+	if q.SpreadBps > 0 {
+		bps := (ask - bid) / mid * 10000.0
+		alerts = append(alerts, e.edgeAlert(ws, st, "spread_wide", on && bps >= q.SpreadBps, q.Cooldown.ToDuration(),
+			AlertSpreadWide, symbol, mid, ts,
+			fmt.Sprintf("%s spread %.0f bps (%.2f x %.2f)", symbol, bps, bid, ask),
+			fmt.Sprintf("Spread. %s spread wide, %.0f basis points.", spoken, bps),
+		)...)
+	}
+
+	// --- Bid/ask size imbalance ---
+	if q.Imbalance > 0 {
+		total := bidSize + askSize
+		imb := 0.0
+		if total > 0 {
+			imb = (bidSize - askSize) / total
+		}
+		enough := on && total > 0 && total >= q.MinSize
+
+		alerts = append(alerts, e.edgeAlert(ws, st, "imbalance_bid", enough && imb >= q.Imbalance, q.Cooldown.ToDuration(),
+			AlertImbalanceBid, symbol, mid, ts,
+			fmt.Sprintf("%s bid heavy %.0f%% (%.0f x %.0f)", symbol, imb*100, bidSize, askSize),
+			fmt.Sprintf("Quote. %s bid heavy, %.0f percent imbalance.", spoken, imb*100),
+		)...)
+		alerts = append(alerts, e.edgeAlert(ws, st, "imbalance_ask", enough && imb <= -q.Imbalance, q.Cooldown.ToDuration(),
+			AlertImbalanceAsk, symbol, mid, ts,
+			fmt.Sprintf("%s ask heavy %.0f%% (%.0f x %.0f)", symbol, -imb*100, bidSize, askSize),
+			fmt.Sprintf("Quote. %s ask heavy, %.0f percent imbalance.", spoken, -imb*100),
+		)...)
+	}
+
+	// --- Midpoint move (relative to midpoint N seconds ago) ---
+	if m := q.MidMove; m != nil {
+		win := m.Window.ToDuration()
+		if win <= 0 {
+			win = 60 * time.Second
+		}
+		st.midHist = appendSampled(st.midHist, point{t: ts, p: mid}, time.Second)
+		st.midHist = pruneByAge(st.midHist, ts.Add(-win-time.Minute))
+
+		oldMid, ok := priceAtOrBefore(st.midHist, ts.Add(-win))
+		if ok && oldMid > 0 {
+			pct := ((mid - oldMid) / oldMid) * 100.0
+			mon := on && m.Sessions.Allows(phase)
+			cooldown := m.Cooldown.ToDuration()
+			if cooldown <= 0 {
+				cooldown = q.Cooldown.ToDuration()
+			}
+
+			if m.UpPct > 0 {
+				alerts = append(alerts, e.edgeAlert(ws, st, "mid_up_"+win.String(), mon && pct >= m.UpPct, cooldown,
+					AlertMidUp, symbol, mid, ts,
+					fmt.Sprintf("%s midpoint up %.2f%% in %s", symbol, pct, win),
+					fmt.Sprintf("Quote. %s midpoint up %.1f percent in the last %d seconds.", spoken, pct, int(win.Seconds())),
+				)...)
+			}
+			if m.DownPct > 0 {
+				alerts = append(alerts, e.edgeAlert(ws, st, "mid_down_"+win.String(), mon && pct <= -math.Abs(m.DownPct), cooldown,
+					AlertMidDown, symbol, mid, ts,
+					fmt.Sprintf("%s midpoint down %.2f%% in %s", symbol, math.Abs(pct), win),
+					fmt.Sprintf("Quote. %s midpoint down %.1f percent in the last %d seconds.", spoken, math.Abs(pct), int(win.Seconds())),
+				)...)
+			}
+		}
+	}
+
+	return alerts
+}
+
+// appendSampled appends p, or overwrites the newest point when it is younger than step.
+// Quotes can arrive hundreds of times a second; history only needs ~1s resolution.
+func appendSampled(h []point, p point, step time.Duration) []point {
+	if n := len(h); n > 0 && p.t.Sub(h[n-1].t) < step {
+		h[n-1].p = p.p
+		return h
+	}
+	return append(h, p)
+}
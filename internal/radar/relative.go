@@ -0,0 +1,72 @@
+package radar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// peerStale is how old the other symbol's last price (benchmark, pair leg) may
+// be for a comparison.
+const peerStale = 2 * time.Minute
+
+// evalRelative runs the relative_strength rule: the symbol's move minus beta x the
+// benchmark's move, over the rule window or since the reference price. The
+// benchmark's state is read as of its latest tick; it is only compared while fresh.
+func (e *Engine) evalRelative(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time, phase session.Phase) []Alert {
+	r := ws.RelativeStrength
+	bws := e.wl.Find(ws.Benchmark)
+	bst := e.state[ws.Benchmark]
+	if bws == nil || bst == nil || bst.lastPrice <= 0 || ts.Sub(bst.lastTime) > peerStale {
+		return nil
+	}
+
+	var from, benchFrom float64
+	var said string
+	if win := r.Window.ToDuration(); win > 0 {
+		var ok1, ok2 bool
+		from, ok1 = priceAtOrBefore(st.hist, ts.Add(-win))
+		benchFrom, ok2 = priceAtOrBefore(bst.hist, ts.Add(-win))
+		if !ok1 || !ok2 {
+			return nil
+		}
+		said = " in the last " + spokenWindow(win)
+	} else {
+		from, _, said = e.baseline(r.Reference, 0, st)
+		benchFrom, _, _ = e.baseline(r.Reference, 0, bst)
+	}
+	if from <= 0 || benchFrom <= 0 {
+		return nil
+	}
+
+	pct := (price - from) / from * 100
+	benchPct := (bst.lastPrice - benchFrom) / benchFrom * 100
+	rel := pct - r.Beta*benchPct
+	on := r.Sessions.Allows(phase)
+
+	detail := fmt.Sprintf("%s %+.2f%%, %s %+.2f%%", symbol, pct, ws.Benchmark, benchPct)
+	if r.Beta != 1 {
+		detail += fmt.Sprintf(", beta %.2f", r.Beta)
+	}
+	spoken, benchSpoken := ws.Spoken(), bws.Spoken()
+
+	var alerts []Alert
+	if r.UpPct > 0 {
+		alerts = append(alerts, e.edgeAlert(ws, st, "rel_up", on && rel >= r.UpPct, r.Cooldown.ToDuration(),
+			AlertRelativeUp, symbol, price, ts,
+			fmt.Sprintf("%s outperforming %s by %.2f pts%s (%s)", symbol, ws.Benchmark, rel, said, detail),
+			fmt.Sprintf("Relative strength. %s outperforming %s by %.1f points%s.", spoken, benchSpoken, rel, said),
+		)...)
+	}
+	if r.DownPct > 0 {
+		alerts = append(alerts, e.edgeAlert(ws, st, "rel_down", on && rel <= -math.Abs(r.DownPct), r.Cooldown.ToDuration(),
+			AlertRelativeDown, symbol, price, ts,
+			fmt.Sprintf("%s underperforming %s by %.2f pts%s (%s)", symbol, ws.Benchmark, math.Abs(rel), said, detail),
+			fmt.Sprintf("Relative strength. %s underperforming %s by %.1f points%s.", spoken, benchSpoken, math.Abs(rel), said),
+		)...)
+	}
+	return alerts
+}
@@ -0,0 +1,408 @@
+package radar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"stockradar/internal/session"
+)
+
+// snapshotVersion is bumped when the file layout changes; other versions are ignored.
+const snapshotVersion = 2
+
+// snapshot is the on-disk form of the engine state (see SaveState / LoadState).
+type snapshot struct {
+	Version int                       `json:"version"`
+	Saved   time.Time                 `json:"saved"`
+	Day     string                    `json:"day"`
+	Phase   session.Phase             `json:"phase,omitempty"`
+	Symbols map[string]symbolSnapshot `json:"symbols"`
+	Pairs   map[string]pairSnapshot   `json:"pairs,omitempty"` // by Pair.Key
+}
+
+type pairSnapshot struct {
+	Hist      []pointSnapshot      `json:"hist,omitempty"`
+	Active    map[string]bool      `json:"active,omitempty"`
+	LastAlert map[string]time.Time `json:"last_alert,omitempty"`
+}
+
+type symbolSnapshot struct {
+	BasePrice float64   `json:"base_price"`
+	LastPrice float64   `json:"last_price"`
+	LastTime  time.Time `json:"last_time"`
+
+	Phase     session.Phase `json:"phase,omitempty"`
+	Day       string        `json:"day,omitempty"`
+	PhaseTime time.Time     `json:"phase_time"`
+
+	PrevClose float64 `json:"prev_close,omitempty"`
+	OpenPrice float64 `json:"open_price,omitempty"`
+	OpenDay   string  `json:"open_day,omitempty"`
+	VWAPPV    float64 `json:"vwap_pv,omitempty"`
+	VWAPVol   float64 `json:"vwap_vol,omitempty"`
+	VWAPFull  bool    `json:"vwap_full,omitempty"`
+	DayVWAP   float64 `json:"day_vwap,omitempty"`
+
+	VWAPSide      string    `json:"vwap_side,omitempty"`
+	VWAPCand      string    `json:"vwap_cand,omitempty"`
+	VWAPCandSince time.Time `json:"vwap_cand_since"`
+
+	High      float64   `json:"high,omitempty"`
+	Low       float64   `json:"low,omitempty"`
+	HighTime  time.Time `json:"high_time"`
+	LowTime   time.Time `json:"low_time"`
+	SessStart time.Time `json:"sess_start"`
+	PBHigh    float64   `json:"pb_high,omitempty"`
+	PBLow     float64   `json:"pb_low,omitempty"`
+
+	ORDay    string          `json:"or_day,omitempty"`
+	ORFrom   time.Time       `json:"or_from"`
+	ORHigh   float64         `json:"or_high,omitempty"`
+	ORLow    float64         `json:"or_low,omitempty"`
+	ORVol    float64         `json:"or_vol,omitempty"`
+	ORRecent []pointSnapshot `json:"or_recent,omitempty"`
+	ORUp     bool            `json:"or_up,omitempty"`
+	ORDown   bool            `json:"or_down,omitempty"`
+
+	Hist     []pointSnapshot `json:"hist,omitempty"`
+	MidHist  []pointSnapshot `json:"mid_hist,omitempty"`
+	VolHist  []pointSnapshot `json:"vol_hist,omitempty"`
+	VolSince time.Time       `json:"vol_since"`
+
+	Bars map[string]barsSnapshot `json:"bars,omitempty"` // by bar length ("1m0s")
+
+	Active    map[string]bool      `json:"active,omitempty"`
+	LastAlert map[string]time.Time `json:"last_alert,omitempty"`
+
+	Escalation map[string]escalationSnapshot `json:"escalation,omitempty"`
+	Pending    map[string]pendingSnapshot    `json:"pending,omitempty"` // confirm counters
+}
+
+type escalationSnapshot struct {
+	Level float64 `json:"level"`
+	Tiers int     `json:"tiers,omitempty"`
+}
+
+type pendingSnapshot struct {
+	Since time.Time `json:"since"`
+	Ticks int       `json:"ticks"`
+}
+
+type barsSnapshot struct {
+	Closed []barSnapshot `json:"closed"`
+	Cur    barSnapshot   `json:"cur"`
+}
+
+type barSnapshot struct {
+	Start time.Time `json:"t"`
+	O     float64   `json:"o"`
+	H     float64   `json:"h"`
+	L     float64   `json:"l"`
+	C     float64   `json:"c"`
+}
+
+type pointSnapshot struct {
+	T time.Time `json:"t"`
+	P float64   `json:"p"`
+	V float64   `json:"v,omitempty"`
+}
+
+// SaveState writes the per-symbol and pair state (baselines, history, edge flags, cooldowns,
+// confirm counters) to path, tagged with the session at now. The file is replaced atomically.
+func (e *Engine) SaveState(path string, now time.Time) error {
+	day, phase := e.sessionKey(now)
+	snap := snapshot{
+		Version: snapshotVersion,
+		Saved:   now,
+		Day:     day,
+		Phase:   phase,
+		Symbols: map[string]symbolSnapshot{},
+	}
+
+	e.mu.Lock()
+	for sym, st := range e.state {
+		snap.Symbols[sym] = symbolSnapshot{
+			BasePrice: st.basePrice,
+			LastPrice: st.lastPrice,
+			LastTime:  st.lastTime,
+			Phase:     st.phase,
+			Day:       st.day,
+			PhaseTime: st.phaseTime,
+			PrevClose: st.prevClose,
+			OpenPrice: st.openPrice,
+			OpenDay:   st.openDay,
+			VWAPPV:    st.vwapPV,
+			VWAPVol:   st.vwapVol,
+			VWAPFull:  st.vwapFull,
+			DayVWAP:   st.dayVWAP,
+
+			VWAPSide:      st.vwapSide,
+			VWAPCand:      st.vwapCand,
+			VWAPCandSince: st.vwapCandSince,
+
+			High:      st.high,
+			Low:       st.low,
+			HighTime:  st.highTime,
+			LowTime:   st.lowTime,
+			SessStart: st.sessStart,
+			PBHigh:    st.pbHigh,
+			PBLow:     st.pbLow,
+
+			ORDay:    st.orDay,
+			ORFrom:   st.orFrom,
+			ORHigh:   st.orHigh,
+			ORLow:    st.orLow,
+			ORVol:    st.orVol,
+			ORRecent: toPointSnapshots(st.orRecent),
+			ORUp:     st.orUp,
+			ORDown:   st.orDown,
+
+			Hist:      toPointSnapshots(st.hist),
+			MidHist:   toPointSnapshots(st.midHist),
+			VolHist:   toPointSnapshots(st.volHist),
+			VolSince:  st.volSince,
+			Bars:      toBarsSnapshots(st.bars),
+			Active:    copyMap(st.active),
+			LastAlert: copyMap(st.lastAlert),
+
+			Escalation: toEscalationSnapshots(st.esc),
+			Pending:    toPendingSnapshots(st.pending),
+		}
+	}
+	for key, st := range e.pairs {
+		if len(st.hist) == 0 && len(st.lastAlert) == 0 {
+			continue
+		}
+		if snap.Pairs == nil {
+			snap.Pairs = map[string]pairSnapshot{}
+		}
+		snap.Pairs[key] = pairSnapshot{
+			Hist:      toPointSnapshots(st.hist),
+			Active:    copyMap(st.active),
+			LastAlert: copyMap(st.lastAlert),
+		}
+	}
+	e.mu.Unlock()
+
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := fmt.Sprintf("%s.tmp-%d", path, time.Now().UnixNano())
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	// atomic replace
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// LoadState restores a snapshot written by SaveState if it was taken in the same
+// session (trading day and phase) as now, and returns the number of symbols restored.
+// A missing file, another session or an unknown version restore nothing without error.
+// Symbols and pairs no longer on the watchlist are dropped.
+func (e *Engine) LoadState(path string, now time.Time) (int, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	if snap.Version != snapshotVersion {
+		e.log.Info().Int("version", snap.Version).Msg("engine state snapshot has another version; starting fresh")
+		return 0, nil
+	}
+	day, phase := e.sessionKey(now)
+	if snap.Day != day || snap.Phase != phase {
+		e.log.Info().
+			Str("saved_day", snap.Day).Str("saved_session", string(snap.Phase)).
+			Msg("engine state snapshot is from another session; starting fresh")
+		return 0, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	restored := 0
+	for sym, ss := range snap.Symbols {
+		ws, st := e.lookup(sym)
+		if ws == nil {
+			continue
+		}
+		st.basePrice = ss.BasePrice
+		st.lastPrice = ss.LastPrice
+		st.lastTime = ss.LastTime
+		st.phase = ss.Phase
+		st.day = ss.Day
+		st.phaseTime = ss.PhaseTime
+		if ss.PrevClose > 0 {
+			st.prevClose = ss.PrevClose
+		}
+		st.openPrice = ss.OpenPrice
+		st.openDay = ss.OpenDay
+		st.vwapPV = ss.VWAPPV
+		st.vwapVol = ss.VWAPVol
+		st.vwapFull = ss.VWAPFull
+		st.dayVWAP = ss.DayVWAP
+		st.vwapSide = ss.VWAPSide
+		st.vwapCand = ss.VWAPCand
+		st.vwapCandSince = ss.VWAPCandSince
+		st.high, st.low = ss.High, ss.Low
+		st.highTime, st.lowTime = ss.HighTime, ss.LowTime
+		st.sessStart = ss.SessStart
+		st.pbHigh, st.pbLow = ss.PBHigh, ss.PBLow
+		st.orDay, st.orFrom = ss.ORDay, ss.ORFrom
+		st.orHigh, st.orLow, st.orVol = ss.ORHigh, ss.ORLow, ss.ORVol
+		st.orRecent = fromPointSnapshots(ss.ORRecent)
+		st.orUp, st.orDown = ss.ORUp, ss.ORDown
+		st.hist = fromPointSnapshots(ss.Hist)
+		st.midHist = fromPointSnapshots(ss.MidHist)
+		st.volHist = fromPointSnapshots(ss.VolHist)
+		st.volSince = ss.VolSince
+		for k, b := range ss.Bars {
+			d, err := time.ParseDuration(k)
+			if err != nil || d <= 0 {
+				continue
+			}
+			bs := newBarSeries(d, e.cfg.BarRetention)
+			for _, c := range b.Closed {
+				bs.closed = append(bs.closed, fromBarSnapshot(c))
+			}
+			if n := len(bs.closed); n > bs.max {
+				bs.closed = bs.closed[n-bs.max:]
+			}
+			bs.cur = fromBarSnapshot(b.Cur)
+			st.bars[d] = bs
+		}
+		for k, v := range ss.Active {
+			st.active[k] = v
+		}
+		for k, v := range ss.LastAlert {
+			st.lastAlert[k] = v
+		}
+		for k, v := range ss.Escalation {
+			st.esc[k] = escalation{level: v.Level, tiers: v.Tiers}
+		}
+		for k, v := range ss.Pending {
+			st.pending[k] = pending{since: v.Since, ticks: v.Ticks}
+		}
+		restored++
+	}
+	for key, ps := range snap.Pairs {
+		st := e.pairs[key]
+		if st == nil {
+			continue // pair no longer configured
+		}
+		st.hist = fromPointSnapshots(ps.Hist)
+		for k, v := range ps.Active {
+			st.active[k] = v
+		}
+		for k, v := range ps.LastAlert {
+			st.lastAlert[k] = v
+		}
+	}
+	return restored, nil
+}
+
+// sessionKey identifies the session at t: trading day and phase with a calendar,
+// the local date otherwise.
+func (e *Engine) sessionKey(t time.Time) (string, session.Phase) {
+	if e.cfg.Calendar == nil {
+		return t.Format("2006-01-02"), ""
+	}
+	return e.cfg.Calendar.Day(t), e.cfg.Calendar.Phase(t)
+}
+
+func toPointSnapshots(h []point) []pointSnapshot {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make([]pointSnapshot, len(h))
+	for i, p := range h {
+		out[i] = pointSnapshot{T: p.t, P: p.p, V: p.v}
+	}
+	return out
+}
+
+func fromPointSnapshots(h []pointSnapshot) []point {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make([]point, len(h))
+	for i, p := range h {
+		out[i] = point{t: p.T, p: p.P, v: p.V}
+	}
+	return out
+}
+
+func toEscalationSnapshots(m map[string]escalation) map[string]escalationSnapshot {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]escalationSnapshot, len(m))
+	for k, x := range m {
+		out[k] = escalationSnapshot{Level: x.level, Tiers: x.tiers}
+	}
+	return out
+}
+
+func toPendingSnapshots(m map[string]pending) map[string]pendingSnapshot {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]pendingSnapshot, len(m))
+	for k, p := range m {
+		out[k] = pendingSnapshot{Since: p.since, Ticks: p.ticks}
+	}
+	return out
+}
+
+func toBarsSnapshots(m map[time.Duration]*barSeries) map[string]barsSnapshot {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]barsSnapshot, len(m))
+	for d, s := range m {
+		b := barsSnapshot{Cur: toBarSnapshot(s.cur)}
+		for _, c := range s.closed {
+			b.Closed = append(b.Closed, toBarSnapshot(c))
+		}
+		out[d.String()] = b
+	}
+	return out
+}
+
+func toBarSnapshot(b bar) barSnapshot {
+	return barSnapshot{Start: b.start, O: b.open, H: b.high, L: b.low, C: b.close}
+}
+
+func fromBarSnapshot(b barSnapshot) bar {
+	return bar{start: b.Start, open: b.O, high: b.H, low: b.L, close: b.C}
+}
+
+func copyMap[V any](m map[string]V) map[string]V {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package radar
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// UpdateTrade feeds one trade print. Only the large_print rule uses trades;
+// price rules stay on aggregates.
+func (e *Engine) UpdateTrade(symbol string, price, size float64, exchange int32, conditions []int32, ts time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ws, st := e.lookup(symbol)
+	if ws == nil || ws.LargePrint == nil {
+		return nil
+	}
+	if price <= 0 || size <= 0 {
+		return nil
+	}
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	r := ws.LargePrint
+	notional := price * size
+	unit := "shares"
+	switch ws.Market {
+	case watchlist.MarketOptions:
+		notional *= 100 // contract multiplier
+		unit = "contracts"
+	case watchlist.MarketCrypto:
+		unit = "coins"
+	}
+
+	big := (r.MinSize > 0 && size >= r.MinSize) || (r.MinNotional > 0 && notional >= r.MinNotional)
+	if !big || !r.Sessions.Allows(e.phaseAt(ts)) || !printMatches(r, exchange, conditions) {
+		return nil
+	}
+
+	// Each block print is its own event, so there is no edge to wait for: back-to-back
+	// prints alert as often as the cooldown allows.
+	return e.cooldownAlert(ws, st, "large_print", r.Cooldown.ToDuration(),
+		AlertLargePrint, symbol, price, ts,
+		fmt.Sprintf("%s block %s %s @ %.2f ($%s)", symbol, strconv.FormatFloat(size, 'f', -1, 64), unit, price, shortDollars(notional)),
+		fmt.Sprintf("Block trade, %s, %s dollars at %s.", ws.Spoken(), spokenDollars(notional), spokenPrice(price)),
+	)
+}
+
+// printMatches applies the rule's exchange and condition filters.
+func printMatches(r *watchlist.LargePrintRule, exchange int32, conditions []int32) bool {
+	if len(r.Exchanges) > 0 && !containsID(r.Exchanges, exchange) {
+		return false
+	}
+	for _, c := range conditions {
+		if containsID(r.ExcludeConditions, c) {
+			return false
+		}
+	}
+	if len(r.Conditions) == 0 {
+		return true
+	}
+	for _, c := range conditions {
+		if containsID(r.Conditions, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []int32, id int32) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
+
+// spokenDollars renders an amount for TTS: "2 million", "2.5 million", "850 thousand".
+func spokenDollars(v float64) string {
+	switch {
+	case v >= 1e9:
+		return trimFloat(v/1e9, 1) + " billion"
+	case v >= 1e6:
+		return trimFloat(v/1e6, 1) + " million"
+	case v >= 1e3:
+		return trimFloat(v/1e3, 0) + " thousand"
+	default:
+		return trimFloat(v, 0)
+	}
+}
+
+// shortDollars renders an amount for messages: "2.03M", "850K".
+func shortDollars(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.0fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// spokenPrice drops trailing zeros so "812.00" is read as "812".
+func spokenPrice(p float64) string {
+	return trimFloat(p, 2)
+}
+
+func trimFloat(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if prec > 0 {
+		for s[len(s)-1] == '0' {
+			s = s[:len(s)-1]
+		}
+		if s[len(s)-1] == '.' {
+			s = s[:len(s)-1]
+		}
+	}
+	return s
+}
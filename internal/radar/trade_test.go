@@ -0,0 +1,37 @@
+package radar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/config"
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+func TestLargePrintBackToBack(t *testing.T) {
+	wl := &watchlist.Watchlist{Symbols: []watchlist.Symbol{{
+		Ticker:     "MU",
+		LargePrint: &watchlist.LargePrintRule{MinSize: 10000, Cooldown: config.Duration(30 * time.Second)},
+	}}}
+	wl.Normalize()
+	e := NewEngine(Config{Calendar: session.New(session.Config{})}, wl, zerolog.Nop())
+	ts := time.Date(2026, 10, 15, 10, 0, 0, 0, session.NewYork())
+
+	// block prints with no ordinary print between them
+	for _, tt := range []struct {
+		after time.Duration
+		want  int
+	}{
+		{0, 1},
+		{10 * time.Second, 0}, // within the cooldown
+		{40 * time.Second, 1},
+	} {
+		got := e.UpdateTrade("MU", 100, 20000, 4, nil, ts.Add(tt.after))
+		if len(got) != tt.want {
+			t.Errorf("block print at +%s: %d alerts, want %d", tt.after, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package radar
+
+import (
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// pending is a condition waiting for confirmation: when it first held and for
+// how many consecutive ticks.
+type pending struct {
+	since time.Time
+	ticks int
+}
+
+// trigger turns a rule's raw condition into the one passed to edgeAlert. enter is
+// the rule's threshold; hold is the looser condition (rearm_pct band) that keeps an
+// active rule active, so it only re-arms after price backs off by the band. An
+// inactive rule becomes active only once enter has held as long as c asks.
+func (e *Engine) trigger(st *symbolState, key string, enter, hold bool, c *watchlist.Confirm, ts time.Time) bool {
+	if st.active[key] {
+		return hold
+	}
+	if !enter {
+		delete(st.pending, key)
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	p, ok := st.pending[key]
+	if !ok {
+		p.since = ts
+	}
+	p.ticks++
+	if p.ticks >= c.Ticks && ts.Sub(p.since) >= c.For.ToDuration() {
+		delete(st.pending, key)
+		return true
+	}
+	st.pending[key] = p
+	return false
+}
@@ -0,0 +1,77 @@
+package radar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"stockradar/internal/watchlist"
+)
+
+// volatility is the symbol's volatility per bar in percent: the standard deviation
+// of close-to-close returns, or the average true range as a percentage of the
+// previous close, over the last Lookback closed bars (ok false until there are enough).
+func (e *Engine) volatility(ws *watchlist.Symbol, st *symbolState) (float64, bool) {
+	v := ws.Volatility
+	s := st.bars[v.Bar.ToDuration()]
+	if s == nil || len(s.closed) < v.Lookback+1 {
+		return 0, false
+	}
+	bars := s.closed[len(s.closed)-v.Lookback-1:]
+
+	vals := make([]float64, 0, v.Lookback)
+	for i := 1; i < len(bars); i++ {
+		prev, b := bars[i-1].close, bars[i]
+		if prev <= 0 {
+			return 0, false
+		}
+		if v.Method == watchlist.VolatilityATR {
+			tr := max(b.high-b.low, math.Abs(b.high-prev), math.Abs(b.low-prev))
+			vals = append(vals, tr/prev*100)
+		} else {
+			vals = append(vals, (b.close-prev)/prev*100)
+		}
+	}
+
+	var sum float64
+	for _, x := range vals {
+		sum += x
+	}
+	mean := sum / float64(len(vals))
+	if v.Method == watchlist.VolatilityATR {
+		return mean, mean > 0
+	}
+	var sq float64
+	for _, x := range vals {
+		sq += (x - mean) * (x - mean)
+	}
+	sd := math.Sqrt(sq / float64(len(vals)-1))
+	return sd, sd > 0
+}
+
+// sigmas expresses a % move over span as a multiple of the per-bar volatility
+// scaled by the square root of time (at least one bar).
+func sigmas(pct, vol float64, span, bar time.Duration) float64 {
+	n := float64(span) / float64(bar)
+	if n < 1 {
+		n = 1
+	}
+	return pct / (vol * math.Sqrt(n))
+}
+
+// sigmaMet checks a sigma threshold (0 = none); unknown volatility never meets one.
+func sigmaMet(threshold float64, known bool, z float64) bool {
+	return threshold <= 0 || (known && z >= threshold)
+}
+
+// sigmaNote is the message and spoken suffix for a rule with a sigma threshold:
+// " (3.1 sigma)" / ", a 3 sigma move", or " (2.5x ATR)" / ", 2.5 times ATR".
+func sigmaNote(ws *watchlist.Symbol, threshold, z float64) (string, string) {
+	if threshold <= 0 {
+		return "", ""
+	}
+	if ws.Volatility.Method == watchlist.VolatilityATR {
+		return fmt.Sprintf(" (%.1fx ATR)", z), fmt.Sprintf(", %s times ATR", spokenRatio(z))
+	}
+	return fmt.Sprintf(" (%.1f sigma)", z), fmt.Sprintf(", a %s sigma move", spokenRatio(z))
+}
@@ -0,0 +1,84 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// evalVolume runs the volume_spike rule for the aggregate just received:
+// volume over the rule window versus the normal volume of a window that long.
+func (e *Engine) evalVolume(ws *watchlist.Symbol, st *symbolState, symbol string, price, volume float64, ts time.Time, phase session.Phase) []Alert {
+	r := ws.VolumeSpike
+	win := r.Window.ToDuration()
+	if win <= 0 {
+		win = 60 * time.Second
+	}
+	keep := e.cfg.HistoryWindow
+	if keep < 3*win {
+		keep = 3 * win
+	}
+
+	if st.volSince.IsZero() {
+		st.volSince = ts
+	}
+	st.volHist = append(st.volHist, point{t: ts, p: price, v: volume})
+	st.volHist = pruneByAge(st.volHist, ts.Add(-keep))
+
+	start := ts.Add(-win)
+	var recent, prior float64
+	for _, p := range st.volHist {
+		if p.t.After(start) {
+			recent += p.v
+		} else {
+			prior += p.v
+		}
+	}
+
+	var normal float64
+	switch r.Baseline {
+	case watchlist.VolumeBaselineADV:
+		normal = st.avgVolume * float64(win) / float64(tradingDay(ws))
+	default:
+		// trailing: volume before the window, scaled to one window length;
+		// needs at least one full window of history first
+		from := st.volSince
+		if oldest := ts.Add(-keep); from.Before(oldest) {
+			from = oldest
+		}
+		if span := start.Sub(from); span >= win {
+			normal = prior * float64(win) / float64(span)
+		}
+	}
+
+	ratio := 0.0
+	if normal > 0 {
+		ratio = recent / normal
+	}
+	spike := r.Sessions.Allows(phase) && normal > 0 && recent >= r.MinVolume && ratio >= r.Multiplier
+
+	return e.edgeAlert(ws, st, "volume_spike", spike, r.Cooldown.ToDuration(),
+		AlertVolumeSpike, symbol, price, ts,
+		fmt.Sprintf("%s volume spike %.1fx normal (%.0f in %s)", symbol, ratio, recent, win),
+		fmt.Sprintf("Volume. %s volume spike, %s times normal.", ws.Spoken(), spokenRatio(ratio)),
+	)
+}
+
+// tradingDay is the time over which average daily volume accrues: the regular
+// session for stocks/options, the whole day for crypto and forex.
+func tradingDay(ws *watchlist.Symbol) time.Duration {
+	if ws.AroundTheClock() || ws.Market == watchlist.MarketForex {
+		return 24 * time.Hour
+	}
+	return 6*time.Hour + 30*time.Minute
+}
+
+// spokenRatio renders a multiple for TTS: "5", "2.5".
+func spokenRatio(r float64) string {
+	if r >= 3 {
+		return fmt.Sprintf("%.0f", r)
+	}
+	return fmt.Sprintf("%.1f", r)
+}
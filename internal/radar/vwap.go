@@ -0,0 +1,77 @@
+package radar
+
+import (
+	"fmt"
+	"time"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+const (
+	sideAbove = "above"
+	sideBelow = "below"
+)
+
+// sessionVWAP is the VWAP of the aggregates since the session VWAP restarted (0 = no volume yet).
+// Until the radar has seen a session start (late start, restart without a snapshot) the
+// feed's day VWAP stands in where it sends one (stocks, options).
+func (st *symbolState) sessionVWAP() float64 {
+	if !st.vwapFull && st.dayVWAP > 0 {
+		return st.dayVWAP
+	}
+	if st.vwapVol <= 0 {
+		return 0
+	}
+	return st.vwapPV / st.vwapVol
+}
+
+// evalVWAPCross runs the vwap_cross rule. A side counts once price clears VWAP by
+// buffer_pct and stays there for min_hold; the first side of a session is taken
+// silently, so only actual crosses are announced.
+func (e *Engine) evalVWAPCross(ws *watchlist.Symbol, st *symbolState, symbol string, price float64, ts time.Time, phase session.Phase) []Alert {
+	r := ws.VWAPCross
+	vwap := st.sessionVWAP()
+	if vwap <= 0 {
+		return nil
+	}
+
+	side := ""
+	switch buf := vwap * r.BufferPct / 100; {
+	case price > vwap+buf:
+		side = sideAbove
+	case price < vwap-buf:
+		side = sideBelow
+	}
+
+	// inside the buffer band: keep the confirmed side and any pending switch
+	if side != "" && side != st.vwapSide {
+		if side != st.vwapCand {
+			st.vwapCand, st.vwapCandSince = side, ts
+		}
+		if ts.Sub(st.vwapCandSince) >= r.MinHold.ToDuration() {
+			if st.vwapSide == "" {
+				// first side of the session: arm the edge without alerting
+				st.active["vwap_"+side] = true
+			}
+			st.vwapSide, st.vwapCand = side, ""
+		}
+	} else if side == st.vwapSide {
+		st.vwapCand = ""
+	}
+
+	on := r.Sessions.Allows(phase)
+	spoken := ws.Spoken()
+	var alerts []Alert
+	alerts = append(alerts, e.edgeAlert(ws, st, "vwap_"+sideAbove, on && st.vwapSide == sideAbove, r.Cooldown.ToDuration(),
+		AlertVWAPAbove, symbol, price, ts,
+		fmt.Sprintf("%s crossed above VWAP %.2f (%.2f)", symbol, vwap, price),
+		fmt.Sprintf("VWAP. %s reclaimed VWAP at %s.", spoken, spokenPrice(price)),
+	)...)
+	alerts = append(alerts, e.edgeAlert(ws, st, "vwap_"+sideBelow, on && st.vwapSide == sideBelow, r.Cooldown.ToDuration(),
+		AlertVWAPBelow, symbol, price, ts,
+		fmt.Sprintf("%s crossed below VWAP %.2f (%.2f)", symbol, vwap, price),
+		fmt.Sprintf("VWAP. %s lost VWAP at %s.", spoken, spokenPrice(price)),
+	)...)
+	return alerts
+}
@@ -0,0 +1,256 @@
+package radar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/session"
+	"stockradar/internal/watchlist"
+)
+
+// Health alerts (watchdog). Distinct from price alerts so the UI and
+// listeners can tell "nothing is happening" from "nothing is arriving".
+const (
+	AlertHealthStale     AlertType = "health_stale"
+	AlertHealthSilent    AlertType = "health_feed_silent"
+	AlertHealthRecovered AlertType = "health_recovered"
+)
+
+type WatchdogConfig struct {
+	SymbolStale time.Duration // per-symbol silence before "no data for X" (watchlist stale_after overrides)
+	FeedSilent  time.Duration // no data for any symbol of a market
+
+	// Stocks/options are expected during these phases of Calendar (default: regular).
+	// Crypto is always expected; forex from Sunday 17:00 to Friday 17:00 New York time.
+	Calendar *session.Calendar
+	Sessions []session.Phase
+}
+
+// Watchdog tracks when each symbol last delivered data and raises health alerts
+// when a symbol or a whole market goes quiet while it is expected to trade.
+//
+// It uses receive time (not tick time), so a delayed feed is not reported as stale.
+type Watchdog struct {
+	cfg WatchdogConfig
+	wl  *watchlist.Watchlist
+	log zerolog.Logger
+	now func() time.Time
+	ny  *time.Location
+
+	mu        sync.Mutex
+	seen      map[string]time.Time // symbol -> last receive time
+	openSince map[string]time.Time // market -> first check that found it open
+	stale     map[string]bool      // symbol
+	silent    map[string]bool      // market
+}
+
+func NewWatchdog(cfg WatchdogConfig, wl *watchlist.Watchlist, now func() time.Time, log zerolog.Logger) *Watchdog {
+	if cfg.SymbolStale <= 0 {
+		cfg.SymbolStale = 2 * time.Minute
+	}
+	if cfg.FeedSilent <= 0 {
+		cfg.FeedSilent = 30 * time.Second
+	}
+	if cfg.Calendar == nil {
+		cfg.Calendar = session.New(session.Config{})
+	}
+	if len(cfg.Sessions) == 0 {
+		cfg.Sessions = []session.Phase{session.Regular}
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Watchdog{
+		cfg:       cfg,
+		wl:        wl,
+		log:       log,
+		now:       now,
+		ny:        session.NewYork(),
+		seen:      map[string]time.Time{},
+		openSince: map[string]time.Time{},
+		stale:     map[string]bool{},
+		silent:    map[string]bool{},
+	}
+}
+
+// Seen records that data arrived for symbol.
+func (w *Watchdog) Seen(symbol string) {
+	if w == nil {
+		return
+	}
+	t := w.now()
+	w.mu.Lock()
+	w.seen[symbol] = t
+	w.mu.Unlock()
+}
+
+// Check evaluates every enabled symbol; call it every few seconds.
+func (w *Watchdog) Check() []Alert {
+	if w == nil || w.wl == nil {
+		return nil
+	}
+	now := w.now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	markets := w.wl.Markets()
+	var alerts []Alert
+	for _, market := range markets {
+		if !w.expected(market, now) {
+			// closed: forget the outage so the next open starts clean
+			delete(w.openSince, market)
+			w.silent[market] = false
+			for _, s := range w.symbols(market) {
+				w.stale[s.Ticker] = false
+			}
+			continue
+		}
+		opened, ok := w.openSince[market]
+		if !ok {
+			opened = now
+			w.openSince[market] = now
+		}
+
+		// --- Whole-market silence ---
+		newest := opened
+		for _, s := range w.symbols(market) {
+			if t := w.seen[s.Ticker]; t.After(newest) {
+				newest = t
+			}
+		}
+		feedName := "Market feed"
+		if len(markets) > 1 {
+			feedName = strings.ToUpper(market[:1]) + market[1:] + " feed"
+		}
+		silent := now.Sub(newest) >= w.cfg.FeedSilent
+		if silent != w.silent[market] {
+			w.silent[market] = silent
+			if silent {
+				alerts = append(alerts, w.alert(AlertHealthSilent, "FEED", now,
+					fmt.Sprintf("%s silent: no data for %s", strings.ToLower(feedName), now.Sub(newest).Round(time.Second)),
+					fmt.Sprintf("Health. %s silent.", feedName),
+				))
+			} else {
+				alerts = append(alerts, w.alert(AlertHealthRecovered, "FEED", now,
+					strings.ToLower(feedName)+" resumed",
+					fmt.Sprintf("Health. %s resumed.", feedName),
+				))
+			}
+		}
+		if silent {
+			// one "feed silent" instead of one alert per symbol
+			continue
+		}
+
+		// --- Per-symbol staleness ---
+		for _, s := range w.symbols(market) {
+			limit := s.StaleAfter.ToDuration()
+			if limit < 0 {
+				continue // disabled for this symbol
+			}
+			if limit == 0 {
+				limit = w.cfg.SymbolStale
+			}
+			last := w.seen[s.Ticker]
+			if last.Before(opened) {
+				last = opened
+			}
+			quiet := now.Sub(last)
+			stale := quiet >= limit
+			if stale == w.stale[s.Ticker] {
+				continue
+			}
+			w.stale[s.Ticker] = stale
+
+			spoken := s.Spoken()
+			if stale {
+				alerts = append(alerts, w.alert(AlertHealthStale, s.Ticker, now,
+					fmt.Sprintf("no data for %s in %s", s.Ticker, quiet.Round(time.Second)),
+					fmt.Sprintf("Health. No data for %s in %s.", spoken, spokenDuration(quiet)),
+				))
+			} else {
+				alerts = append(alerts, w.alert(AlertHealthRecovered, s.Ticker, now,
+					fmt.Sprintf("data resumed for %s", s.Ticker),
+					fmt.Sprintf("Health. Data resumed for %s.", spoken),
+				))
+			}
+		}
+	}
+	return alerts
+}
+
+func (w *Watchdog) alert(t AlertType, symbol string, now time.Time, msg, speak string) Alert {
+	w.log.Warn().Str("symbol", symbol).Str("type", string(t)).Msg(msg)
+	return Alert{Type: t, Symbol: symbol, Time: now, Message: msg, SpeakText: speak}
+}
+
+// symbols returns the enabled watchlist symbols of one market.
+func (w *Watchdog) symbols(market string) []*watchlist.Symbol {
+	var out []*watchlist.Symbol
+	for i := range w.wl.Symbols {
+		s := &w.wl.Symbols[i]
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if s.Market == market {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// expected reports whether market normally trades at t.
+func (w *Watchdog) expected(market string, t time.Time) bool {
+	switch market {
+	case watchlist.MarketCrypto:
+		return true
+	case watchlist.MarketForex:
+		ny := t.In(w.ny)
+		switch ny.Weekday() {
+		case time.Saturday:
+			return false
+		case time.Sunday:
+			return ny.Hour() >= 17
+		case time.Friday:
+			return ny.Hour() < 17
+		default:
+			return true
+		}
+	default:
+		phase := w.cfg.Calendar.Phase(t)
+		for _, p := range w.cfg.Sessions {
+			if p == phase {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// spokenDuration renders d for TTS: "45 seconds", "2 minutes", "1 hour 5 minutes".
+func spokenDuration(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return plural(int(d.Seconds()), "second")
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	default:
+		h := int(d.Hours())
+		m := int(d.Minutes()) % 60
+		if m == 0 {
+			return plural(h, "hour")
+		}
+		return plural(h, "hour") + " " + plural(m, "minute")
+	}
+}
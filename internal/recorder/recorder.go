@@ -0,0 +1,221 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/feed"
+)
+
+type Config struct {
+	Dir      string
+	MaxBytes int64 // rotate after this many (uncompressed) bytes; 0 = only rotate by date
+	Gzip     bool
+	Location *time.Location // file dates are trading days here (the session time zone); default local
+}
+
+// Recorder appends every feed message to date-stamped, size-rotated JSONL files:
+//
+//	<dir>/ticks-2025-01-02-001.jsonl(.gz)
+//
+// Each line is a feed.Tick (so files can be fed back with -replay) plus
+// the receive time and the raw Massive message.
+//
+// A nil *Recorder is valid and records nothing.
+type Recorder struct {
+	cfg Config
+	log zerolog.Logger
+
+	mu      sync.Mutex
+	f       *os.File
+	gz      *gzip.Writer
+	bw      *bufio.Writer
+	day     string
+	seq     int
+	written int64
+	failed  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type line struct {
+	feed.Tick
+	Recv time.Time `json:"recv"`
+	Raw  any       `json:"raw,omitempty"`
+}
+
+func New(cfg Config, log zerolog.Logger) (*Recorder, error) {
+	if cfg.Dir == "" {
+		cfg.Dir = "./data/ticks"
+	}
+	if cfg.MaxBytes < 0 {
+		cfg.MaxBytes = 0
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{
+		cfg:  cfg,
+		log:  log,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	// Flush periodically so a crash loses at most ~1s of data.
+	go func() {
+		defer close(r.done)
+		tk := time.NewTicker(time.Second)
+		defer tk.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-tk.C:
+				r.mu.Lock()
+				r.flushLocked()
+				r.mu.Unlock()
+			}
+		}
+	}()
+
+	return r, nil
+}
+
+// Write records one tick together with its raw source message (t.Raw).
+func (r *Recorder) Write(t feed.Tick) {
+	if r == nil {
+		return
+	}
+
+	now := time.Now()
+	b, err := json.Marshal(line{Tick: t, Recv: now, Raw: t.Raw})
+	if err != nil {
+		r.log.Debug().Err(err).Msg("recorder: marshal failed")
+		return
+	}
+	b = append(b, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := now.In(r.cfg.Location).Format("2006-01-02")
+	if r.bw == nil || day != r.day || (r.cfg.MaxBytes > 0 && r.written >= r.cfg.MaxBytes) {
+		if err := r.rotateLocked(day); err != nil {
+			if !r.failed {
+				r.log.Error().Err(err).Str("dir", r.cfg.Dir).Msg("recorder: cannot open file; recording paused")
+			}
+			r.failed = true
+			return
+		}
+		r.failed = false
+	}
+
+	n, err := r.bw.Write(b)
+	r.written += int64(n)
+	if err != nil && !r.failed {
+		r.log.Error().Err(err).Msg("recorder: write failed")
+		r.failed = true
+	}
+}
+
+// Close flushes and closes the current file.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	close(r.stop)
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeLocked()
+}
+
+func (r *Recorder) rotateLocked(day string) error {
+	if err := r.closeLocked(); err != nil {
+		r.log.Warn().Err(err).Msg("recorder: error closing previous file")
+	}
+
+	if day != r.day {
+		r.day = day
+		r.seq = 0
+	}
+
+	ext := ".jsonl"
+	if r.cfg.Gzip {
+		ext += ".gz"
+	}
+
+	// Never overwrite an earlier file (e.g. after a restart on the same day):
+	// O_EXCL fails on an existing name, so step to the next sequence number.
+	var path string
+	var f *os.File
+	for {
+		r.seq++
+		path = filepath.Join(r.cfg.Dir, fmt.Sprintf("ticks-%s-%03d%s", day, r.seq, ext))
+		var err error
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+	}
+
+	var w io.Writer = f
+	if r.cfg.Gzip {
+		r.gz = gzip.NewWriter(f)
+		w = r.gz
+	}
+	r.f = f
+	r.bw = bufio.NewWriterSize(w, 64*1024)
+	r.written = 0
+
+	r.log.Info().Str("path", path).Msg("recorder: writing")
+	return nil
+}
+
+func (r *Recorder) flushLocked() {
+	if r.bw == nil {
+		return
+	}
+	_ = r.bw.Flush()
+	if r.gz != nil {
+		_ = r.gz.Flush()
+	}
+}
+
+func (r *Recorder) closeLocked() error {
+	if r.f == nil {
+		return nil
+	}
+	var firstErr error
+	if err := r.bw.Flush(); err != nil {
+		firstErr = err
+	}
+	if r.gz != nil {
+		if err := r.gz.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := r.f.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	r.f, r.gz, r.bw = nil, nil, nil
+	return firstErr
+}
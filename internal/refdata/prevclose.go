@@ -0,0 +1,154 @@
+package refdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrevCloser returns the previous regular-session close for a watchlist ticker.
+type PrevCloser interface {
+	PrevClose(ctx context.Context, ticker string) (float64, error)
+}
+
+var ErrNotFound = errors.New("previous close not found")
+
+// File is a local YAML (or JSON) map of ticker to value: previous closes,
+// average daily volumes, ...
+//
+//	MU: 95.20
+//	AAPL: 231.4
+//	X:BTC-USD: 64210
+//
+// Call LoadFile again to pick up a file refreshed by e.g. a nightly job.
+type File struct {
+	prices map[string]float64
+}
+
+func LoadFile(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]float64
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	prices := make(map[string]float64, len(raw))
+	for k, v := range raw {
+		if v > 0 {
+			prices[strings.ToUpper(strings.TrimSpace(k))] = v
+		}
+	}
+	return &File{prices: prices}, nil
+}
+
+// Lookup returns the value listed for ticker.
+func (f *File) Lookup(ticker string) (float64, bool) {
+	v, ok := f.prices[strings.ToUpper(ticker)]
+	return v, ok
+}
+
+func (f *File) PrevClose(_ context.Context, ticker string) (float64, error) {
+	if p, ok := f.Lookup(ticker); ok {
+		return p, nil
+	}
+	return 0, ErrNotFound
+}
+
+// MassiveREST fetches GET {BaseURL}/v2/aggs/ticker/{ticker}/prev.
+// Point BaseURL at a local stub to test without the real API.
+type MassiveREST struct {
+	BaseURL string
+	APIKey  string
+	HTTP    *http.Client
+}
+
+func NewMassiveREST(baseURL, apiKey string, timeout time.Duration) *MassiveREST {
+	if baseURL == "" {
+		baseURL = "https://api.massive.com"
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &MassiveREST{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		HTTP:    &http.Client{Timeout: timeout},
+	}
+}
+
+type prevResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		Ticker string  `json:"T"`
+		Close  float64 `json:"c"`
+	} `json:"results"`
+}
+
+func (m *MassiveREST) PrevClose(ctx context.Context, ticker string) (float64, error) {
+	u := fmt.Sprintf("%s/v2/aggs/ticker/%s/prev?adjusted=true", m.BaseURL, url.PathEscape(restTicker(ticker)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	if m.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.APIKey)
+	}
+
+	resp, err := m.HTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("massive prev close %s: %s: %s", ticker, resp.Status, strings.TrimSpace(string(b)))
+	}
+	var pr prevResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return 0, fmt.Errorf("massive prev close %s: %w", ticker, err)
+	}
+	if len(pr.Results) == 0 || pr.Results[0].Close <= 0 {
+		return 0, ErrNotFound
+	}
+	return pr.Results[0].Close, nil
+}
+
+// restTicker maps watchlist pairs to REST tickers: X:BTC-USD -> X:BTCUSD.
+func restTicker(t string) string {
+	if strings.HasPrefix(t, "X:") || strings.HasPrefix(t, "C:") {
+		return strings.ReplaceAll(t, "-", "")
+	}
+	return t
+}
+
+// Chain asks each source in order and returns the first hit.
+type Chain []PrevCloser
+
+func (c Chain) PrevClose(ctx context.Context, ticker string) (float64, error) {
+	var firstErr error
+	for _, src := range c {
+		p, err := src.PrevClose(ctx, ticker)
+		if err == nil && p > 0 {
+			return p, nil
+		}
+		if firstErr == nil || errors.Is(firstErr, ErrNotFound) {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = ErrNotFound
+	}
+	return 0, firstErr
+}
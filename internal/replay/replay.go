@@ -0,0 +1,283 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"stockradar/internal/feed"
+)
+
+type Config struct {
+	Path string // tick file, or a directory of them (e.g. the recorder output dir)
+
+	// Speed multiplier: 1 = real time, 10 = ten times faster, 0 = as fast as possible.
+	Speed float64
+
+	// Quiet stretches longer than this (overnight, halts) are compressed to MaxGap;
+	// 0 keeps the recorded gaps.
+	MaxGap time.Duration
+}
+
+// Player reads tick files (one feed.Tick JSON object per line), sleeping between
+// records to reproduce the original pacing. It is a feed.MarketSource.
+type Player struct {
+	cfg Config
+	log zerolog.Logger
+
+	ticks chan feed.Tick
+	errs  chan error
+
+	mu       sync.Mutex
+	subs     map[string]bool // empty = everything in the file
+	clock    time.Time       // recorded time of the last dispatched record
+	clockAt  time.Time       // wall time when clock was set
+	finished bool
+}
+
+func NewPlayer(cfg Config, log zerolog.Logger) *Player {
+	if cfg.Speed < 0 {
+		cfg.Speed = 0
+	}
+	if cfg.MaxGap < 0 {
+		cfg.MaxGap = 0
+	}
+	return &Player{
+		cfg:   cfg,
+		log:   log,
+		ticks: make(chan feed.Tick, 1024),
+		errs:  make(chan error, 1),
+		subs:  map[string]bool{},
+	}
+}
+
+func (p *Player) Ticks() <-chan feed.Tick { return p.ticks }
+func (p *Player) Errors() <-chan error    { return p.errs }
+
+// Subscribe limits playback to tickers; without it every symbol in the file is played.
+func (p *Player) Subscribe(tickers ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range tickers {
+		p.subs[strings.ToUpper(strings.TrimSpace(t))] = true
+	}
+	return nil
+}
+
+// Start plays the input once in the background; Ticks() is closed when it is exhausted.
+func (p *Player) Start(ctx context.Context) error {
+	if _, err := inputFiles(p.cfg.Path); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(p.ticks)
+
+		err := p.Run(ctx, func(t feed.Tick) {
+			p.mu.Lock()
+			skip := len(p.subs) > 0 && !p.subs[t.Symbol]
+			p.mu.Unlock()
+			if skip {
+				return
+			}
+			select {
+			case p.ticks <- t:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			p.errs <- err
+		}
+	}()
+
+	return nil
+}
+
+// Now returns the replay clock: the recorded time of the last record,
+// advanced by wall time elapsed since then (scaled by Speed).
+// Before the first record it returns the wall clock.
+func (p *Player) Now() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.clock.IsZero() {
+		return time.Now()
+	}
+	if p.cfg.Speed <= 0 || p.finished {
+		return p.clock
+	}
+	// recorded time since the last record, capped like the gaps Run sleeps through
+	elapsed := time.Duration(float64(time.Since(p.clockAt)) * p.cfg.Speed)
+	if p.cfg.MaxGap > 0 && elapsed > p.cfg.MaxGap {
+		elapsed = p.cfg.MaxGap
+	}
+	return p.clock.Add(elapsed)
+}
+
+// Run plays the file (or every tick file in the directory, in name order) once.
+// It returns nil when the input is exhausted, or ctx.Err() if cancelled.
+func (p *Player) Run(ctx context.Context, fn func(feed.Tick)) error {
+	paths, err := inputFiles(p.cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	var prev time.Time
+	played, skipped := 0, 0
+	for _, path := range paths {
+		if err := p.playFile(ctx, path, &prev, &played, &skipped, fn); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.finished = true
+	p.mu.Unlock()
+
+	p.log.Info().
+		Str("path", p.cfg.Path).
+		Int("files", len(paths)).
+		Int("records", played).
+		Int("skipped", skipped).
+		Msg("replay finished")
+
+	if played == 0 {
+		return errors.New("replay input contained no usable records")
+	}
+	return nil
+}
+
+func (p *Player) playFile(ctx context.Context, path string, prev *time.Time, played, skipped *int, fn func(feed.Tick)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rec feed.Tick
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			*skipped++
+			p.log.Debug().Err(err).Str("file", path).Int("line", lineNo).Msg("replay: skipping bad line")
+			continue
+		}
+		rec.Symbol = strings.ToUpper(strings.TrimSpace(rec.Symbol))
+		if rec.Kind == "" {
+			rec.Kind = feed.KindAgg
+		}
+		if rec.Symbol == "" || rec.Price <= 0 || rec.Time.IsZero() {
+			*skipped++
+			continue
+		}
+
+		// pacing
+		if p.cfg.Speed > 0 && !prev.IsZero() {
+			gap := rec.Time.Sub(*prev)
+			if p.cfg.MaxGap > 0 && gap > p.cfg.MaxGap {
+				gap = p.cfg.MaxGap
+			}
+			if gap > 0 {
+				if err := sleepCtx(ctx, time.Duration(float64(gap)/p.cfg.Speed)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if rec.Time.After(*prev) {
+			*prev = rec.Time
+		}
+
+		p.mu.Lock()
+		p.clock = *prev
+		p.clockAt = time.Now()
+		p.mu.Unlock()
+
+		fn(rec)
+		*played++
+	}
+
+	if err := sc.Err(); err != nil {
+		// A recorder killed mid-write leaves a gzip stream without its trailer; keep what we got.
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			p.log.Warn().Str("file", path).Msg("replay: file truncated; continuing")
+			return nil
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// inputFiles expands a directory into its *.jsonl / *.jsonl.gz files sorted by name
+// (recorder file names sort chronologically).
+func inputFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := strings.ToLower(e.Name())
+		if e.IsDir() || !(strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl.gz")) {
+			continue
+		}
+		out = append(out, filepath.Join(path, e.Name()))
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no .jsonl files in %s", path)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
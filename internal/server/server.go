@@ -50,6 +50,10 @@ type Event struct {
 
 	// For pulse/debug
 	DeltaPct float64 `json:"delta_pct,omitempty"`
+
+	// feed_status
+	State   string `json:"state,omitempty"` // connecting | connected | reconnecting
+	Attempt int    `json:"attempt,omitempty"`
 }
 
 type Server struct {
@@ -37,6 +37,7 @@ const indexHTML = `<!doctype html>
     .event .msg { font-size: 14px; margin-top: 2px; }
     .event.up { border-left-color: #18a558; background: rgba(24,165,88,0.08); }
     .event.down { border-left-color: #d64545; background: rgba(214,69,69,0.08); }
+    .event.health { border-left-color: #d49a00; background: rgba(212,154,0,0.10); }
     .mono { font-family: ui-monospace, SFMono-Regular, Menlo, Monaco, Consolas, monospace; }
 
     /* Cloud box with FULL FRAME */
@@ -59,6 +60,7 @@ const indexHTML = `<!doctype html>
 
   <div class="row">
     <span class="pill">SSE: <span id="sseStatus" class="mono">connecting…</span></span>
+    <span class="pill">Feed: <span id="feedStatus" class="mono">—</span></span>
     <span class="pill">Voice alerts: <span id="audioStatus" class="mono">disabled</span></span>
     <button id="enableAudio">Enable Audio</button>
     <button id="muteAudio" class="secondary">Mute</button>
@@ -101,6 +103,7 @@ const indexHTML = `<!doctype html>
 const appJS = `
 (function(){
   const sseStatus = document.getElementById('sseStatus');
+  const feedStatus = document.getElementById('feedStatus');
   const audioStatus = document.getElementById('audioStatus');
   const cloudSoundStatus = document.getElementById('cloudSoundStatus');
   const cloudVoiceStatus = document.getElementById('cloudVoiceStatus');
@@ -823,6 +826,7 @@ const appJS = `
     }
 
     d.className = 'event' + (dir === 'up' ? ' up' : (dir === 'down' ? ' down' : ''));
+    if (('' + (ev.type || '')).indexOf('health_') === 0) d.className = 'event health';
 
     const ts = ev.time ? new Date(ev.time).toLocaleTimeString() : '';
     const cache = ev.cache_hit ? 'cache' : 'new';
@@ -918,6 +922,9 @@ const appJS = `
         onCloudPulse(ev);
         return;
       }
+      if (ev.type === 'feed_status') {
+        feedStatus.textContent = ev.state + (ev.attempt ? ' (' + ev.attempt + ')' : '');
+      }
 
       addEvent(ev);
       if (ev.audio_url) enqueue(ev.audio_url);
@@ -0,0 +1,244 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Phase is the US equity session a moment falls into.
+type Phase string
+
+const (
+	Closed  Phase = "closed"
+	Pre     Phase = "pre"     // pre-market (e.g. 04:00-09:30 ET)
+	Regular Phase = "regular" // 09:30-16:00 ET (13:00 on early-close days)
+	After   Phase = "after"   // after-hours (e.g. 16:00-20:00 ET)
+)
+
+// ParsePhase accepts the phase names plus common spellings
+// ("premarket", "rth", "post", "afterhours", ...). ok is false for unknown names.
+func ParsePhase(s string) (Phase, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "pre", "premarket", "pre-market", "pre_market":
+		return Pre, true
+	case "regular", "rth", "open", "day":
+		return Regular, true
+	case "after", "afterhours", "after-hours", "after_hours", "post", "postmarket", "post-market":
+		return After, true
+	case "closed", "overnight":
+		return Closed, true
+	default:
+		return "", false
+	}
+}
+
+// Spoken is the phrase used when a phase begins ("Regular session open.").
+func (p Phase) Spoken() string {
+	switch p {
+	case Pre:
+		return "Pre-market session open."
+	case Regular:
+		return "Regular session open."
+	case After:
+		return "Regular session closed. After-hours session open."
+	default:
+		return "Market closed."
+	}
+}
+
+type Config struct {
+	Location *time.Location // default America/New_York
+
+	// Boundaries as time since local midnight.
+	PreOpen    time.Duration // 04:00
+	Open       time.Duration // 09:30
+	Close      time.Duration // 16:00
+	AfterClose time.Duration // 20:00
+
+	Holidays []Holiday
+}
+
+// Holiday is a full closure, or an early close when EarlyClose is set
+// (regular session ends at EarlyClose; after-hours runs its normal length after it).
+type Holiday struct {
+	Date       string `yaml:"date"` // 2006-01-02
+	Name       string `yaml:"name"`
+	EarlyClose string `yaml:"early_close,omitempty"` // "13:00"
+}
+
+// Calendar maps times to session phases.
+type Calendar struct {
+	cfg      Config
+	holidays map[string]Holiday
+	early    map[string]time.Duration
+}
+
+func New(cfg Config) *Calendar {
+	if cfg.Location == nil {
+		cfg.Location = NewYork()
+	}
+	if cfg.Open <= 0 || cfg.Close <= cfg.Open {
+		cfg.Open = 9*time.Hour + 30*time.Minute
+		cfg.Close = 16 * time.Hour
+	}
+	if cfg.PreOpen <= 0 || cfg.PreOpen > cfg.Open {
+		cfg.PreOpen = 4 * time.Hour
+	}
+	if cfg.AfterClose < cfg.Close {
+		cfg.AfterClose = 20 * time.Hour
+	}
+
+	c := &Calendar{
+		cfg:      cfg,
+		holidays: map[string]Holiday{},
+		early:    map[string]time.Duration{},
+	}
+	for _, h := range cfg.Holidays {
+		d := strings.TrimSpace(h.Date)
+		if h.EarlyClose != "" {
+			// LoadHolidays rejects a bad early_close; never read one as a closure
+			if ec, ok := ClockTime(h.EarlyClose); ok {
+				c.early[d] = ec
+			}
+			continue
+		}
+		c.holidays[d] = h
+	}
+	return c
+}
+
+// LoadHolidays reads a holiday file:
+//
+//	holidays:
+//	  - { date: "2025-12-25", name: "Christmas" }
+//	  - { date: "2025-12-24", name: "Christmas Eve", early_close: "13:00" }
+func LoadHolidays(path string) ([]Holiday, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Holidays []Holiday `yaml:"holidays"`
+	}
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	for _, h := range f.Holidays {
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(h.Date)); err != nil {
+			return nil, fmt.Errorf("holiday %q: invalid date %q", h.Name, h.Date)
+		}
+		if h.EarlyClose != "" {
+			if _, ok := ClockTime(h.EarlyClose); !ok {
+				return nil, fmt.Errorf("holiday %q: invalid early_close %q (use HH:MM)", h.Name, h.EarlyClose)
+			}
+		}
+	}
+	sort.Slice(f.Holidays, func(i, j int) bool { return f.Holidays[i].Date < f.Holidays[j].Date })
+	return f.Holidays, nil
+}
+
+func (c *Calendar) Location() *time.Location { return c.cfg.Location }
+
+// Phase returns the session phase at t. Weekends and full holidays are Closed.
+func (c *Calendar) Phase(t time.Time) Phase {
+	lt := t.In(c.cfg.Location)
+	if lt.Weekday() == time.Saturday || lt.Weekday() == time.Sunday {
+		return Closed
+	}
+	day := lt.Format("2006-01-02")
+	if _, ok := c.holidays[day]; ok {
+		return Closed
+	}
+
+	closeAt := c.cfg.Close
+	afterAt := c.cfg.AfterClose
+	if ec, ok := c.early[day]; ok && ec < closeAt {
+		afterAt -= closeAt - ec
+		closeAt = ec
+	}
+
+	tod := sinceMidnight(lt)
+	switch {
+	case tod < c.cfg.PreOpen:
+		return Closed
+	case tod < c.cfg.Open:
+		return Pre
+	case tod < closeAt:
+		return Regular
+	case tod < afterAt:
+		return After
+	default:
+		return Closed
+	}
+}
+
+// Day is the calendar date of t in the session time zone (the trading day key).
+func (c *Calendar) Day(t time.Time) string {
+	return t.In(c.cfg.Location).Format("2006-01-02")
+}
+
+// OpenAt is the regular-session open on t's calendar date (session time zone).
+func (c *Calendar) OpenAt(t time.Time) time.Time {
+	lt := t.In(c.cfg.Location)
+	o := c.cfg.Open
+	return time.Date(lt.Year(), lt.Month(), lt.Day(),
+		int(o/time.Hour), int(o%time.Hour/time.Minute), int(o%time.Minute/time.Second), 0, c.cfg.Location)
+}
+
+// Holiday reports the full-closure holiday on t's date, if any.
+func (c *Calendar) Holiday(t time.Time) (Holiday, bool) {
+	h, ok := c.holidays[c.Day(t)]
+	return h, ok
+}
+
+// Tracker reports phase changes as time advances.
+type Tracker struct {
+	cal   *Calendar
+	phase Phase
+	init  bool
+}
+
+func NewTracker(cal *Calendar) *Tracker { return &Tracker{cal: cal} }
+
+// Update returns the phase at now and whether it differs from the previous call.
+// The first call only initializes (changed is false).
+func (t *Tracker) Update(now time.Time) (phase Phase, changed bool) {
+	p := t.cal.Phase(now)
+	if !t.init {
+		t.init = true
+		t.phase = p
+		return p, false
+	}
+	if p == t.phase {
+		return p, false
+	}
+	t.phase = p
+	return p, true
+}
+
+// ClockTime parses "HH:MM" as time since midnight.
+func ClockTime(s string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// NewYork is the US equity time zone (fixed EST if tzdata is unavailable).
+func NewYork() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*3600)
+	}
+	return loc
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPhase(t *testing.T) {
+	cal := New(Config{Holidays: []Holiday{
+		{Date: "2026-11-26", Name: "Thanksgiving"},
+		{Date: "2026-11-27", Name: "Day after Thanksgiving", EarlyClose: "13:00"},
+	}})
+	at := func(day, clock string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04:05", day+" "+clock, NewYork())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	tests := []struct {
+		day, clock string
+		want       Phase
+	}{
+		// an ordinary Thursday
+		{"2026-10-15", "03:59:59", Closed},
+		{"2026-10-15", "04:00:00", Pre},
+		{"2026-10-15", "09:29:59", Pre},
+		{"2026-10-15", "09:30:00", Regular},
+		{"2026-10-15", "15:59:59", Regular},
+		{"2026-10-15", "16:00:00", After},
+		{"2026-10-15", "19:59:59", After},
+		{"2026-10-15", "20:00:00", Closed},
+		// weekend
+		{"2026-10-17", "10:00:00", Closed},
+		{"2026-10-18", "10:00:00", Closed},
+		// full closure
+		{"2026-11-26", "10:00:00", Closed},
+		// early close: regular ends at 13:00, after-hours keeps its four hours
+		{"2026-11-27", "09:30:00", Regular},
+		{"2026-11-27", "12:59:59", Regular},
+		{"2026-11-27", "13:00:00", After},
+		{"2026-11-27", "16:59:59", After},
+		{"2026-11-27", "17:00:00", Closed},
+	}
+	for _, tt := range tests {
+		if got := cal.Phase(at(tt.day, tt.clock)); got != tt.want {
+			t.Errorf("Phase(%s %s) = %s, want %s", tt.day, tt.clock, got, tt.want)
+		}
+	}
+
+	if h, ok := cal.Holiday(at("2026-11-26", "12:00:00")); !ok || h.Name != "Thanksgiving" {
+		t.Errorf("Holiday(Thanksgiving) = %+v, %v", h, ok)
+	}
+	if _, ok := cal.Holiday(at("2026-11-27", "12:00:00")); ok {
+		t.Error("an early close is reported as a full closure")
+	}
+	// a UTC instant falls on the New York date
+	if got := cal.Day(time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)); got != "2026-10-15" {
+		t.Errorf("Day = %s, want 2026-10-15", got)
+	}
+}
+
+func TestLoadHolidays(t *testing.T) {
+	write := func(body string) string {
+		path := filepath.Join(t.TempDir(), "holidays.yaml")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	got, err := LoadHolidays(write(`holidays:
+  - { date: "2026-12-25", name: "Christmas" }
+  - { date: "2026-12-24", name: "Christmas Eve", early_close: "13:00" }
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Date != "2026-12-24" || got[1].Date != "2026-12-25" {
+		t.Errorf("LoadHolidays = %+v, want both days sorted by date", got)
+	}
+
+	for _, tt := range []struct{ body, want string }{
+		{`holidays: [{ date: "12/25/2026", name: "Christmas" }]`, "invalid date"},
+		{`holidays: [{ date: "2026-12-24", name: "Christmas Eve", early_close: "1pm" }]`, "invalid early_close"},
+	} {
+		if _, err := LoadHolidays(write(tt.body)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("LoadHolidays(%s) error = %v, want %q", tt.body, err, tt.want)
+		}
+	}
+}
+
+func TestNewIgnoresBadEarlyClose(t *testing.T) {
+	cal := New(Config{Holidays: []Holiday{{Date: "2026-12-24", EarlyClose: "1pm"}}})
+	noon := time.Date(2026, 12, 24, 12, 0, 0, 0, NewYork())
+	if got := cal.Phase(noon); got != Regular {
+		t.Errorf("Phase = %s, want %s (a bad early_close is not a closure)", got, Regular)
+	}
+}
@@ -2,52 +2,454 @@ package watchlist
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"stockradar/internal/config"
+	"stockradar/internal/session"
 )
 
 type Watchlist struct {
+	// Default market for symbols without a market/prefix (stocks | crypto | forex | options).
+	Market  string   `yaml:"market,omitempty"`
 	Symbols []Symbol `yaml:"symbols"`
+
+	// Spread rules between two watchlist symbols.
+	Pairs []Pair `yaml:"pairs,omitempty"`
+}
+
+// Pair watches the spread between two watchlist symbols, A/B (ratio) or
+// A - Hedge x B (spread), against its own rolling mean over Window: alerts when
+// it is ZScore standard deviations or Pct percent away from the mean.
+type Pair struct {
+	A       string          `yaml:"a"`
+	B       string          `yaml:"b"`
+	Name    string          `yaml:"name,omitempty"`    // spoken; default "A versus B"
+	Formula string          `yaml:"formula,omitempty"` // ratio (default) | spread
+	Hedge   float64         `yaml:"hedge,omitempty"`   // spread: B multiplier (default 1)
+	Window  config.Duration `yaml:"window"`            // rolling window (default 30m)
+	Sample  config.Duration `yaml:"sample,omitempty"`  // one value per sample (default 5s)
+
+	ZScore   float64         `yaml:"zscore"`
+	Pct      float64         `yaml:"pct"`
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+const (
+	PairRatio  = "ratio"
+	PairSpread = "spread"
+)
+
+// Key identifies the pair: "MU/WDC" (ratio) or "MU-WDC" (spread).
+func (p *Pair) Key() string {
+	if p.Formula == PairSpread {
+		return p.A + "-" + p.B
+	}
+	return p.A + "/" + p.B
 }
 
+// Markets and their Massive ticker prefixes.
+const (
+	MarketStocks  = "stocks"
+	MarketCrypto  = "crypto"  // X:BTC-USD
+	MarketForex   = "forex"   // C:EUR-USD
+	MarketOptions = "options" // O:SPY251219C00600000
+)
+
 type Symbol struct {
 	Ticker  string `yaml:"ticker"`
 	Name    string `yaml:"name,omitempty"`
 	Enabled *bool  `yaml:"enabled,omitempty"`
 
+	// stocks | crypto | forex | options; inferred from the ticker prefix
+	// (X:, C:, O:) or the watchlist/config default when omitted.
+	Market string `yaml:"market,omitempty"`
+
+	// Price the rules evaluate: "last" (aggregate close, default) or "mid" (quote midpoint;
+	// steadier for illiquid names where odd prints trigger false moves).
+	PriceSource string `yaml:"price_source,omitempty"`
+
 	BaseChange *BaseChangeRule `yaml:"base_change,omitempty"`
-	Momentum   *MomentumRule   `yaml:"momentum,omitempty"`
+	Momentum   Momentums       `yaml:"momentum,omitempty"` // one rule or a list of windows
 	PriceCross *PriceCrossRule `yaml:"price_cross,omitempty"`
+	Levels     []PriceLevel    `yaml:"levels,omitempty"`
+	Quote      *QuoteRule      `yaml:"quote,omitempty"`
+
+	VolumeSpike  *VolumeSpikeRule  `yaml:"volume_spike,omitempty"`
+	LargePrint   *LargePrintRule   `yaml:"large_print,omitempty"`
+	VWAPCross    *VWAPCrossRule    `yaml:"vwap_cross,omitempty"`
+	MACross      []MACrossRule     `yaml:"ma_cross,omitempty"`
+	NewHighLow   *NewHighLowRule   `yaml:"new_high_low,omitempty"`
+	Pullback     *PullbackRule     `yaml:"pullback,omitempty"`
+	OpeningRange *OpeningRangeRule `yaml:"opening_range,omitempty"`
+
+	// How up_sigma / down_sigma thresholds measure volatility (defaults when omitted).
+	Volatility *Volatility `yaml:"volatility,omitempty"`
+
+	// Symbol the relative_strength rule compares against (e.g. QQQ); must be on the watchlist.
+	Benchmark        string                `yaml:"benchmark,omitempty"`
+	RelativeStrength *RelativeStrengthRule `yaml:"relative_strength,omitempty"`
 
 	// fallback if rule cooldown omitted
 	Cooldown config.Duration `yaml:"cooldown,omitempty"`
+
+	// Watchdog: warn after this long without data (0 = global watchdog.symbol_stale,
+	// negative = never; useful for names that can go minutes without a print).
+	StaleAfter config.Duration `yaml:"stale_after,omitempty"`
 }
 
 type BaseChangeRule struct {
-	UpPct    float64        `yaml:"up_pct"`
-	DownPct  float64        `yaml:"down_pct"`
-	Cooldown config.Duration `yaml:"cooldown"`
+	UpPct     float64         `yaml:"up_pct"`
+	DownPct   float64         `yaml:"down_pct"`
+	UpSigma   float64         `yaml:"up_sigma,omitempty"` // multiples of the symbol's volatility (see Volatility)
+	DownSigma float64         `yaml:"down_sigma,omitempty"`
+	Cooldown  config.Duration `yaml:"cooldown"`
+	Sessions  Sessions        `yaml:"sessions,omitempty"`
+	Escalate  *Escalation     `yaml:"escalate,omitempty"`
+	RearmPct  float64         `yaml:"rearm_pct,omitempty"` // re-arm only after falling back this far below up_pct / down_pct
+	Confirm   *Confirm        `yaml:"confirm,omitempty"`
+
+	// What the percentage is measured against (see Reference* constants).
+	Reference string  `yaml:"reference,omitempty"`
+	Price     float64 `yaml:"price,omitempty"` // reference: fixed
+}
+
+const (
+	ReferenceFirstTick   = "first_tick"   // first tick after start / session reset (default)
+	ReferencePrevClose   = "prev_close"   // previous regular-session close ("% on the day")
+	ReferenceSessionOpen = "session_open" // official open of the day
+	ReferenceVWAP        = "vwap"         // session VWAP
+	ReferenceFixed       = "fixed"        // Price
+)
+
+// parseReference normalizes a base_change reference name ("" = first tick).
+func parseReference(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "prev_close", "previous_close", "prev-close", "close", "day":
+		return ReferencePrevClose
+	case "session_open", "open", "session-open":
+		return ReferenceSessionOpen
+	case "vwap":
+		return ReferenceVWAP
+	case "fixed", "price":
+		return ReferenceFixed
+	default:
+		return ReferenceFirstTick
+	}
 }
 
 type MomentumRule struct {
-	Window   config.Duration `yaml:"window"`
-	UpPct    float64         `yaml:"up_pct"`
-	DownPct  float64         `yaml:"down_pct"`
-	Cooldown config.Duration `yaml:"cooldown"`
+	Window    config.Duration `yaml:"window"`
+	UpPct     float64         `yaml:"up_pct"`
+	DownPct   float64         `yaml:"down_pct"`
+	UpSigma   float64         `yaml:"up_sigma,omitempty"` // multiples of the symbol's volatility (see Volatility)
+	DownSigma float64         `yaml:"down_sigma,omitempty"`
+	Cooldown  config.Duration `yaml:"cooldown"`
+	Sessions  Sessions        `yaml:"sessions,omitempty"`
+	Escalate  *Escalation     `yaml:"escalate,omitempty"`
+	RearmPct  float64         `yaml:"rearm_pct,omitempty"` // re-arm only after falling back this far below up_pct / down_pct
+	Confirm   *Confirm        `yaml:"confirm,omitempty"`
 }
 
+// Confirm delays a rule until its condition has held for For (tick time) and for
+// Ticks consecutive ticks, which filters single bad prints.
+type Confirm struct {
+	For   config.Duration `yaml:"for"`
+	Ticks int             `yaml:"ticks"`
+}
+
+// Escalation re-alerts a move that keeps extending while its rule stays triggered
+// ("MU now up 3 percent"), bypassing the cooldown: every StepPct percent beyond the
+// last alerted move, or each time it doubles (whichever comes first).
+type Escalation struct {
+	StepPct float64 `yaml:"step_pct"`
+	Double  bool    `yaml:"double"`
+	Max     int     `yaml:"max"` // re-alerts per move (0 = no limit)
+}
+
+// Next is the move size (percent) that triggers the re-alert after one at last.
+func (x *Escalation) Next(last float64) float64 {
+	next := math.Inf(1)
+	if x.StepPct > 0 {
+		next = last + x.StepPct
+	}
+	if x.Double && 2*last < next {
+		next = 2 * last
+	}
+	return next
+}
+
+// Momentums accepts a single momentum rule (momentum: {window: 60s, ...}) or a
+// list of them, one per window.
+type Momentums []MomentumRule
+
+func (m *Momentums) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		var r MomentumRule
+		if err := value.Decode(&r); err != nil {
+			return err
+		}
+		*m = Momentums{r}
+		return nil
+	}
+	var rs []MomentumRule
+	if err := value.Decode(&rs); err != nil {
+		return err
+	}
+	*m = rs
+	return nil
+}
+
+// PriceCrossRule is the single above/below form of levels; Normalize turns it into Levels.
 type PriceCrossRule struct {
 	Above    float64         `yaml:"above"`
 	Below    float64         `yaml:"below"`
 	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+	RearmPct float64         `yaml:"rearm_pct,omitempty"`
+	Confirm  *Confirm        `yaml:"confirm,omitempty"`
+}
+
+// PriceLevel is a named price ("yesterday high", "round 800") announced when crossed.
+type PriceLevel struct {
+	Price     float64         `yaml:"price"`
+	Label     string          `yaml:"label,omitempty"`
+	Direction string          `yaml:"direction,omitempty"` // above | below | both (default)
+	Cooldown  config.Duration `yaml:"cooldown"`
+	Sessions  Sessions        `yaml:"sessions,omitempty"`
+	RearmPct  float64         `yaml:"rearm_pct,omitempty"` // re-arm only after price moves back this far (% of the level)
+	Confirm   *Confirm        `yaml:"confirm,omitempty"`
+}
+
+const (
+	DirectionAbove = "above"
+	DirectionBelow = "below"
+	DirectionBoth  = "both"
+)
+
+// Above reports whether crossing up through the level is announced.
+func (l *PriceLevel) Above() bool { return l.Direction != DirectionBelow }
+
+// Below reports whether crossing down through the level is announced.
+func (l *PriceLevel) Below() bool { return l.Direction != DirectionAbove }
+
+// VolumeSpikeRule compares aggregate volume over Window with the normal volume
+// for a window of that length.
+type VolumeSpikeRule struct {
+	Window     config.Duration `yaml:"window"`     // default 60s
+	Multiplier float64         `yaml:"multiplier"` // alert at this many times normal (default 3)
+	MinVolume  float64         `yaml:"min_volume"` // ignore windows with less volume than this
+
+	// "trailing" (default): average over the rest of the history window.
+	// "adv": average daily volume from reference.avg_volume_file, spread evenly over the session.
+	Baseline string `yaml:"baseline,omitempty"`
+
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+const (
+	VolumeBaselineTrailing = "trailing"
+	VolumeBaselineADV      = "adv"
+)
+
+// LargePrintRule flags single trades (block prints) by size or dollar value.
+// It needs the trades stream, which is subscribed automatically.
+type LargePrintRule struct {
+	MinSize     float64 `yaml:"min_size"`     // shares (contracts for options, coins for crypto)
+	MinNotional float64 `yaml:"min_notional"` // price x size in dollars (x100 for options)
+
+	// Massive trade condition / exchange IDs. Conditions: only prints carrying one of
+	// these; ExcludeConditions: drop prints carrying any of these; Exchanges: only these venues.
+	Conditions        []int32 `yaml:"conditions,omitempty"`
+	ExcludeConditions []int32 `yaml:"exclude_conditions,omitempty"`
+	Exchanges         []int32 `yaml:"exchanges,omitempty"`
+
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+// VWAPCrossRule fires when price moves to the other side of the session VWAP
+// ("reclaimed VWAP" / "lost VWAP").
+type VWAPCrossRule struct {
+	BufferPct float64         `yaml:"buffer_pct"` // must clear VWAP by this much (e.g. 0.1 = 0.1%)
+	MinHold   config.Duration `yaml:"min_hold"`   // and stay on the new side this long
+	Cooldown  config.Duration `yaml:"cooldown"`
+	Sessions  Sessions        `yaml:"sessions,omitempty"`
+}
+
+// MACrossRule watches moving averages of Bar-length bars: Fast crossing Slow,
+// or (Fast = 0) the bar close crossing the Slow average. Evaluated as bars close.
+type MACrossRule struct {
+	Bar  config.Duration `yaml:"bar"`  // default 1m
+	Type string          `yaml:"type"` // sma (default) | ema
+	Fast int             `yaml:"fast"` // bars; 0 = price vs the slow average
+	Slow int             `yaml:"slow"`
+
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+// Volatility is the symbol's realized volatility on Bar-length bars over the last
+// Lookback bars: the standard deviation of bar-to-bar returns, or the average true
+// range. Sigma thresholds scale it to the rule's window by the square root of time.
+type Volatility struct {
+	Method   string          `yaml:"method"`   // stdev (default) | atr
+	Bar      config.Duration `yaml:"bar"`      // default 1m
+	Lookback int             `yaml:"lookback"` // bars, default 30
+}
+
+const (
+	VolatilityStdev = "stdev"
+	VolatilityATR   = "atr"
+)
+
+// UsesSigma reports whether a base_change or momentum threshold is in volatility multiples.
+func (s *Symbol) UsesSigma() bool {
+	if b := s.BaseChange; b != nil && (b.UpSigma > 0 || b.DownSigma > 0) {
+		return true
+	}
+	for _, m := range s.Momentum {
+		if m.UpSigma > 0 || m.DownSigma > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	MATypeSMA = "sma"
+	MATypeEMA = "ema"
+)
+
+// NewHighLowRule announces new session highs and lows ("MU new high of day").
+// A break counts only if the previous extreme stood for MinAge and is exceeded by
+// MinPct, so a steady grind higher does not speak on every tick.
+type NewHighLowRule struct {
+	MinPct   float64         `yaml:"min_pct"` // beyond the previous extreme, % (default 0)
+	MinAge   config.Duration `yaml:"min_age"` // previous extreme must be at least this old (default 60s)
+	Warmup   config.Duration `yaml:"warmup"`  // quiet after the session starts (default 5m)
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
 }
 
-func Load(path string) (*Watchlist, error) {
+// OpeningRangeRule records the high and low of the first Window of the regular
+// session and announces the first break above and below it each day. With
+// VolumeMultiplier the breakout's volume over VolumeWindow must be that many
+// times the opening range's average for a window that long.
+type OpeningRangeRule struct {
+	Window           config.Duration `yaml:"window"`                      // default 15m
+	BufferPct        float64         `yaml:"buffer_pct,omitempty"`        // must clear the range by this much
+	VolumeMultiplier float64         `yaml:"volume_multiplier,omitempty"` // 0 = no volume confirmation
+	VolumeWindow     config.Duration `yaml:"volume_window,omitempty"`     // default 1m
+}
+
+// PullbackRule fires when price falls DownPct off its running high or rallies UpPct
+// off its running low ("LRCX down 2 percent from its high"). The high and low run
+// from the session start, or (Since: alert) restart at each alert of that side.
+type PullbackRule struct {
+	DownPct  float64         `yaml:"down_pct"` // off the high
+	UpPct    float64         `yaml:"up_pct"`   // off the low
+	Since    string          `yaml:"since,omitempty"`
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+const (
+	SinceSession = "session"
+	SinceAlert   = "alert"
+)
+
+// RelativeStrengthRule compares the symbol's move with its benchmark's: the
+// symbol's % change minus Beta x the benchmark's, in percentage points. With a
+// Window the moves are over that window, otherwise since Reference (as base_change).
+type RelativeStrengthRule struct {
+	Window    config.Duration `yaml:"window,omitempty"`
+	Reference string          `yaml:"reference,omitempty"` // without window; fixed is not supported
+	Beta      float64         `yaml:"beta,omitempty"`      // default 1
+	UpPct     float64         `yaml:"up_pct"`              // outperforming by this many points
+	DownPct   float64         `yaml:"down_pct"`            // underperforming by this many points
+	Cooldown  config.Duration `yaml:"cooldown"`
+	Sessions  Sessions        `yaml:"sessions,omitempty"`
+}
+
+// QuoteRule evaluates the NBBO (requires a quote subscription, enabled automatically).
+type QuoteRule struct {
+	SpreadBps float64 `yaml:"spread_bps"` // alert when (ask-bid)/mid exceeds this many basis points
+	Imbalance float64 `yaml:"imbalance"`  // 0..1: |bid size - ask size| / (bid size + ask size)
+	MinSize   float64 `yaml:"min_size"`   // ignore imbalance when bid+ask size is below this
+
+	// Midpoint move over a window (same fields as momentum).
+	MidMove *MomentumRule `yaml:"mid_move,omitempty"`
+
+	Cooldown config.Duration `yaml:"cooldown"`
+	Sessions Sessions        `yaml:"sessions,omitempty"`
+}
+
+// Sessions limits a rule to session phases: sessions: [regular] or sessions: [pre, after].
+// Empty means every phase. Names are checked when the watchlist loads.
+type Sessions []session.Phase
+
+func (s *Sessions) UnmarshalYAML(value *yaml.Node) error {
+	var names []string
+	switch value.Kind {
+	case yaml.ScalarNode:
+		names = []string{value.Value} // sessions: regular
+	default:
+		if err := value.Decode(&names); err != nil {
+			return err
+		}
+	}
+	out := make(Sessions, 0, len(names))
+	for _, n := range names {
+		p, ok := session.ParsePhase(n)
+		if !ok {
+			return fmt.Errorf("unknown session %q (use pre, regular, after, closed)", n)
+		}
+		out = append(out, p)
+	}
+	*s = out
+	return nil
+}
+
+// Allows reports whether a rule limited to s is active in phase p.
+func (s Sessions) Allows(p session.Phase) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, x := range s {
+		if x == p {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	PriceSourceLast = "last"
+	PriceSourceMid  = "mid"
+)
+
+// WantsTrades reports whether the symbol's rules need the trades stream.
+func (s *Symbol) WantsTrades() bool {
+	return s != nil && s.LargePrint != nil && s.Market != MarketForex
+}
+
+// WantsQuotes reports whether the symbol needs a quote subscription.
+func (s *Symbol) WantsQuotes() bool {
+	return s != nil && (s.Quote != nil || s.PriceSource == PriceSourceMid)
+}
+
+// Load reads a watchlist. defaultMarket (usually massive.market from config.yaml)
+// applies when neither the watchlist nor the symbol says otherwise.
+func Load(path string, defaultMarket string) (*Watchlist, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -56,32 +458,264 @@ func Load(path string) (*Watchlist, error) {
 	if err := yaml.Unmarshal(b, &wl); err != nil {
 		return nil, err
 	}
+	if strings.TrimSpace(wl.Market) == "" {
+		wl.Market = defaultMarket
+	}
 	wl.Normalize()
 	if len(wl.Symbols) == 0 {
 		return nil, errors.New("watchlist empty")
 	}
+	for _, s := range wl.Symbols {
+		if s.RelativeStrength == nil {
+			continue
+		}
+		if s.Benchmark == "" {
+			return nil, fmt.Errorf("%s: relative_strength needs a benchmark", s.Ticker)
+		}
+		b := wl.Find(s.Benchmark)
+		if s.Benchmark == s.Ticker || b == nil {
+			return nil, fmt.Errorf("%s: benchmark %s must be another symbol on the watchlist", s.Ticker, s.Benchmark)
+		}
+		if b.Enabled != nil && !*b.Enabled {
+			// never subscribed: the rule would stay silent for good
+			return nil, fmt.Errorf("%s: benchmark %s is disabled", s.Ticker, s.Benchmark)
+		}
+	}
+	for _, p := range wl.Pairs {
+		a, b := wl.Find(p.A), wl.Find(p.B)
+		if a == nil || b == nil {
+			return nil, fmt.Errorf("pair %s: both legs must be on the watchlist", p.Key())
+		}
+		for _, leg := range []*Symbol{a, b} {
+			if leg.Enabled != nil && !*leg.Enabled {
+				return nil, fmt.Errorf("pair %s: leg %s is disabled", p.Key(), leg.Ticker)
+			}
+		}
+	}
+	for i := range wl.Symbols {
+		if err := wl.Symbols[i].checkDuplicates(); err != nil {
+			return nil, fmt.Errorf("%s: %w", wl.Symbols[i].Ticker, err)
+		}
+	}
 	return &wl, nil
 }
 
+// checkDuplicates rejects rules the engine could not tell apart (it keys their
+// alert state by price, window or averages): two levels watching the same side
+// of one price, two momentum rules over one window, or two identical MA crosses.
+func (s *Symbol) checkDuplicates() error {
+	levels := map[string]bool{}
+	for i := range s.Levels {
+		l := &s.Levels[i]
+		for _, side := range []string{DirectionAbove, DirectionBelow} {
+			if side == DirectionAbove && !l.Above() || side == DirectionBelow && !l.Below() {
+				continue
+			}
+			k := fmt.Sprintf("%s %.4f", side, l.Price)
+			if levels[k] {
+				return fmt.Errorf("more than one level %s %.4f", side, l.Price)
+			}
+			levels[k] = true
+		}
+	}
+	windows := map[config.Duration]bool{}
+	for _, m := range s.Momentum {
+		win := m.Window
+		if win.ToDuration() <= 0 {
+			win = config.Duration(60 * 1e9)
+		}
+		if windows[win] {
+			return fmt.Errorf("more than one momentum rule over %s", win.ToDuration())
+		}
+		windows[win] = true
+	}
+	mas := map[string]bool{}
+	for _, m := range s.MACross {
+		k := fmt.Sprintf("%s %d/%d %s", m.Type, m.Fast, m.Slow, m.Bar.ToDuration())
+		if mas[k] {
+			return fmt.Errorf("more than one ma_cross %s", k)
+		}
+		mas[k] = true
+	}
+	return nil
+}
+
 func (w *Watchlist) Normalize() {
 	seen := map[string]bool{}
 	out := make([]Symbol, 0, len(w.Symbols))
 
+	w.Market = ParseMarket(w.Market)
+
 	for _, s := range w.Symbols {
 		s.Ticker = strings.ToUpper(strings.TrimSpace(s.Ticker))
 		if s.Ticker == "" {
 			continue
 		}
+		switch {
+		case strings.TrimSpace(s.Market) != "":
+			s.Market = ParseMarket(s.Market)
+		case hasMarketPrefix(s.Ticker):
+			s.Market = MarketOf(s.Ticker)
+		default:
+			s.Market = w.Market
+		}
+		s.Ticker = CanonicalTicker(s.Ticker, s.Market)
+
+		switch strings.ToLower(strings.TrimSpace(s.PriceSource)) {
+		case "mid", "midpoint", "quote":
+			s.PriceSource = PriceSourceMid
+		default:
+			s.PriceSource = PriceSourceLast
+		}
+
 		if seen[s.Ticker] {
 			continue
 		}
 		seen[s.Ticker] = true
 
+		if b := s.BaseChange; b != nil {
+			b.Reference = parseReference(b.Reference)
+			if b.Reference == ReferenceFixed && b.Price <= 0 {
+				b.Reference = ReferenceFirstTick
+			}
+			b.Escalate = normalizeEscalation(b.Escalate)
+			b.RearmPct = math.Max(b.RearmPct, 0)
+			b.Confirm = normalizeConfirm(b.Confirm)
+		}
+		for i := range s.Momentum {
+			m := &s.Momentum[i]
+			m.Escalate = normalizeEscalation(m.Escalate)
+			m.RearmPct = math.Max(m.RearmPct, 0)
+			m.Confirm = normalizeConfirm(m.Confirm)
+		}
+
+		if v := s.VolumeSpike; v != nil {
+			switch strings.ToLower(strings.TrimSpace(v.Baseline)) {
+			case "adv", "avg_volume", "average_daily_volume", "daily":
+				v.Baseline = VolumeBaselineADV
+			default:
+				v.Baseline = VolumeBaselineTrailing
+			}
+			if v.Multiplier <= 0 {
+				v.Multiplier = 3
+			}
+		}
+
+		if pc := s.PriceCross; pc != nil {
+			if pc.Above > 0 {
+				s.Levels = append(s.Levels, PriceLevel{Price: pc.Above, Direction: DirectionAbove, Cooldown: pc.Cooldown, Sessions: pc.Sessions, RearmPct: pc.RearmPct, Confirm: pc.Confirm})
+			}
+			if pc.Below > 0 {
+				s.Levels = append(s.Levels, PriceLevel{Price: pc.Below, Direction: DirectionBelow, Cooldown: pc.Cooldown, Sessions: pc.Sessions, RearmPct: pc.RearmPct, Confirm: pc.Confirm})
+			}
+			s.PriceCross = nil
+		}
+		levels := s.Levels[:0]
+		for _, l := range s.Levels {
+			if l.Price <= 0 {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(l.Direction)) {
+			case "above", "up":
+				l.Direction = DirectionAbove
+			case "below", "down":
+				l.Direction = DirectionBelow
+			default:
+				l.Direction = DirectionBoth
+			}
+			l.Label = strings.TrimSpace(l.Label)
+			l.RearmPct = math.Max(l.RearmPct, 0)
+			l.Confirm = normalizeConfirm(l.Confirm)
+			levels = append(levels, l)
+		}
+		s.Levels = levels
+
+		mas := s.MACross[:0]
+		for _, m := range s.MACross {
+			if m.Fast > m.Slow {
+				m.Fast, m.Slow = m.Slow, m.Fast
+			}
+			if m.Slow <= 0 || m.Fast == m.Slow {
+				continue
+			}
+			if m.Bar.ToDuration() <= 0 {
+				m.Bar = config.Duration(60 * 1e9)
+			}
+			if strings.EqualFold(strings.TrimSpace(m.Type), MATypeEMA) {
+				m.Type = MATypeEMA
+			} else {
+				m.Type = MATypeSMA
+			}
+			mas = append(mas, m)
+		}
+		s.MACross = mas
+
+		s.Benchmark = w.canonical(s.Benchmark)
+		if r := s.RelativeStrength; r != nil {
+			r.Reference = parseReference(r.Reference)
+			if r.Reference == ReferenceFixed {
+				r.Reference = ReferenceFirstTick
+			}
+			if r.Beta == 0 {
+				r.Beta = 1
+			}
+		}
+
+		if s.UsesSigma() {
+			if s.Volatility == nil {
+				s.Volatility = &Volatility{}
+			}
+			v := s.Volatility
+			if strings.EqualFold(strings.TrimSpace(v.Method), VolatilityATR) {
+				v.Method = VolatilityATR
+			} else {
+				v.Method = VolatilityStdev
+			}
+			if v.Bar.ToDuration() <= 0 {
+				v.Bar = config.Duration(60 * 1e9)
+			}
+			if v.Lookback < 2 {
+				v.Lookback = 30
+			}
+		} else {
+			s.Volatility = nil
+		}
+
+		if or := s.OpeningRange; or != nil {
+			if or.Window.ToDuration() <= 0 {
+				or.Window = config.Duration(15 * 60 * 1e9)
+			}
+			if or.VolumeWindow.ToDuration() <= 0 {
+				or.VolumeWindow = config.Duration(60 * 1e9)
+			}
+			or.BufferPct = math.Max(or.BufferPct, 0)
+		}
+
+		if pb := s.Pullback; pb != nil {
+			if strings.EqualFold(strings.TrimSpace(pb.Since), SinceAlert) {
+				pb.Since = SinceAlert
+			} else {
+				pb.Since = SinceSession
+			}
+		}
+
+		if h := s.NewHighLow; h != nil {
+			if h.MinAge.ToDuration() <= 0 {
+				h.MinAge = config.Duration(60 * 1e9)
+			}
+			if h.Warmup.ToDuration() <= 0 {
+				h.Warmup = config.Duration(300 * 1e9)
+			}
+			if h.MinPct < 0 {
+				h.MinPct = 0
+			}
+		}
+
 		// defaults if rule not provided
-		if s.BaseChange == nil && s.Momentum == nil && s.PriceCross == nil {
+		if s.BaseChange == nil && len(s.Momentum) == 0 && len(s.Levels) == 0 && s.Quote == nil && s.VolumeSpike == nil && s.LargePrint == nil && s.VWAPCross == nil && len(s.MACross) == 0 && s.NewHighLow == nil && s.Pullback == nil && s.OpeningRange == nil && s.RelativeStrength == nil {
 			// sensible default: base-change + momentum
 			s.BaseChange = &BaseChangeRule{UpPct: 1.0, DownPct: 1.0, Cooldown: config.Duration(90 * 1e9)}
-			s.Momentum = &MomentumRule{Window: config.Duration(60 * 1e9), UpPct: 0.4, DownPct: 0.4, Cooldown: config.Duration(60 * 1e9)}
+			s.Momentum = Momentums{{Window: config.Duration(60 * 1e9), UpPct: 0.4, DownPct: 0.4, Cooldown: config.Duration(60 * 1e9)}}
 		}
 		out = append(out, s)
 	}
@@ -89,6 +723,69 @@ func (w *Watchlist) Normalize() {
 	// stable order
 	sort.Slice(out, func(i, j int) bool { return out[i].Ticker < out[j].Ticker })
 	w.Symbols = out
+
+	pairs := w.Pairs[:0]
+	seenPair := map[string]bool{}
+	for _, p := range w.Pairs {
+		p.A, p.B = w.canonical(p.A), w.canonical(p.B)
+		if p.A == "" || p.B == "" || p.A == p.B || (p.ZScore <= 0 && p.Pct <= 0) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(p.Formula), PairSpread) {
+			p.Formula = PairSpread
+		} else {
+			p.Formula = PairRatio
+		}
+		if p.Hedge == 0 {
+			p.Hedge = 1
+		}
+		if p.Window.ToDuration() <= 0 {
+			p.Window = config.Duration(30 * 60 * 1e9)
+		}
+		if p.Sample.ToDuration() <= 0 {
+			p.Sample = config.Duration(5 * 1e9)
+		}
+		p.Name = strings.TrimSpace(p.Name)
+		if seenPair[p.Key()] {
+			continue
+		}
+		seenPair[p.Key()] = true
+		pairs = append(pairs, p)
+	}
+	w.Pairs = pairs
+}
+
+// normalizeEscalation drops an escalation without a step (nil = none).
+func normalizeEscalation(x *Escalation) *Escalation {
+	if x == nil || (x.StepPct <= 0 && !x.Double) {
+		return nil
+	}
+	if x.Max < 0 {
+		x.Max = 0
+	}
+	return x
+}
+
+// normalizeConfirm drops a confirmation that asks for nothing (nil = none).
+func normalizeConfirm(c *Confirm) *Confirm {
+	if c == nil || (c.For.ToDuration() <= 0 && c.Ticks <= 1) {
+		return nil
+	}
+	return c
+}
+
+// canonical normalizes a ticker referenced by a rule (benchmark, pair leg) like a
+// symbol's own: market from the prefix, else the watchlist default.
+func (w *Watchlist) canonical(t string) string {
+	t = strings.ToUpper(strings.TrimSpace(t))
+	if t == "" {
+		return ""
+	}
+	m := w.Market
+	if hasMarketPrefix(t) {
+		m = MarketOf(t)
+	}
+	return CanonicalTicker(t, m)
 }
 
 func (w *Watchlist) Tickers() []string {
@@ -108,6 +805,111 @@ func (w *Watchlist) Tickers() []string {
 	return t
 }
 
+// PrevCloseTickers returns enabled tickers whose base_change or relative_strength is
+// measured vs the previous close (including the benchmarks of the latter).
+func (w *Watchlist) PrevCloseTickers() []string {
+	if w == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	for i := range w.Symbols {
+		s := &w.Symbols[i]
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if s.BaseChange != nil && s.BaseChange.Reference == ReferencePrevClose {
+			seen[s.Ticker] = true
+		}
+		if r := s.RelativeStrength; r != nil && r.Window == 0 && r.Reference == ReferencePrevClose {
+			seen[s.Ticker] = true
+			seen[s.Benchmark] = true
+		}
+	}
+	t := make([]string, 0, len(seen))
+	for k := range seen {
+		t = append(t, k)
+	}
+	sort.Strings(t)
+	return t
+}
+
+// AvgVolumeTickers returns enabled tickers whose volume_spike baseline is average daily volume.
+func (w *Watchlist) AvgVolumeTickers() []string {
+	if w == nil {
+		return nil
+	}
+	var t []string
+	for i := range w.Symbols {
+		s := &w.Symbols[i]
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if s.VolumeSpike != nil && s.VolumeSpike.Baseline == VolumeBaselineADV {
+			t = append(t, s.Ticker)
+		}
+	}
+	sort.Strings(t)
+	return t
+}
+
+// TradeTickers returns enabled tickers whose rules need trades, sorted.
+func (w *Watchlist) TradeTickers() []string {
+	if w == nil {
+		return nil
+	}
+	var t []string
+	for i := range w.Symbols {
+		s := &w.Symbols[i]
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if s.WantsTrades() {
+			t = append(t, s.Ticker)
+		}
+	}
+	sort.Strings(t)
+	return t
+}
+
+// QuoteTickers returns enabled tickers that need quotes, sorted.
+func (w *Watchlist) QuoteTickers() []string {
+	if w == nil {
+		return nil
+	}
+	var t []string
+	for i := range w.Symbols {
+		s := &w.Symbols[i]
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if s.WantsQuotes() {
+			t = append(t, s.Ticker)
+		}
+	}
+	sort.Strings(t)
+	return t
+}
+
+// Markets returns the distinct markets of enabled symbols, sorted.
+func (w *Watchlist) Markets() []string {
+	if w == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range w.Symbols {
+		if s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if !seen[s.Market] {
+			seen[s.Market] = true
+			out = append(out, s.Market)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 func (w *Watchlist) Find(ticker string) *Symbol {
 	if w == nil {
 		return nil
@@ -121,4 +923,102 @@ func (w *Watchlist) Find(ticker string) *Symbol {
 	return nil
 }
 
+// ParseMarket maps config spellings to a market name (unknown => stocks).
+func ParseMarket(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "crypto":
+		return MarketCrypto
+	case "forex", "fx", "currencies":
+		return MarketForex
+	case "options":
+		return MarketOptions
+	default:
+		return MarketStocks
+	}
+}
+
+// MarketOf infers the market of a canonical ticker from its prefix.
+func MarketOf(ticker string) string {
+	switch {
+	case strings.HasPrefix(ticker, "X:"):
+		return MarketCrypto
+	case strings.HasPrefix(ticker, "C:"):
+		return MarketForex
+	case strings.HasPrefix(ticker, "O:"):
+		return MarketOptions
+	default:
+		return MarketStocks
+	}
+}
+
+func hasMarketPrefix(ticker string) bool {
+	return MarketOf(ticker) != MarketStocks
+}
 
+// CanonicalTicker returns the Massive subscription form of a ticker:
+//
+//	crypto  "btc/usd", "X:BTC-USD" -> "X:BTC-USD"
+//	forex   "EUR/USD"              -> "C:EUR-USD"
+//	options "SPY251219C00600000"   -> "O:SPY251219C00600000"
+//	stocks  unchanged (upper-cased)
+func CanonicalTicker(ticker, market string) string {
+	t := strings.ToUpper(strings.TrimSpace(ticker))
+	if i := strings.Index(t, ":"); i >= 0 && i <= 2 {
+		t = t[i+1:]
+	}
+	switch market {
+	case MarketCrypto:
+		return "X:" + strings.Trim(strings.ReplaceAll(t, "/", "-"), "-")
+	case MarketForex:
+		return "C:" + strings.Trim(strings.ReplaceAll(t, "/", "-"), "-")
+	case MarketOptions:
+		return "O:" + t
+	default:
+		return t
+	}
+}
+
+// AroundTheClock reports whether the symbol trades 24/7 (crypto), so
+// session-based logic (market hours, session resets) should not apply to it.
+func (s *Symbol) AroundTheClock() bool {
+	return s != nil && s.Market == MarketCrypto
+}
+
+// Spoken is the ticker as it should be read aloud: market prefixes removed,
+// pairs split ("BTC USD"), option contracts summarized ("SPY 600 call").
+func (s *Symbol) Spoken() string {
+	if s == nil {
+		return ""
+	}
+	t := s.Ticker
+	if i := strings.Index(t, ":"); i >= 0 && i <= 2 {
+		t = t[i+1:]
+	}
+	switch s.Market {
+	case MarketCrypto, MarketForex:
+		return strings.ReplaceAll(t, "-", " ")
+	case MarketOptions:
+		return spokenOption(t)
+	default:
+		return t
+	}
+}
+
+// spokenOption turns an OCC symbol (ROOT + YYMMDD + C/P + strike*1000, 8 digits)
+// into "ROOT <strike> call|put"; anything else is returned unchanged.
+func spokenOption(occ string) string {
+	if len(occ) < 16 {
+		return occ
+	}
+	root := occ[:len(occ)-15]
+	cp := occ[len(occ)-9]
+	strike, err := strconv.ParseFloat(occ[len(occ)-8:], 64)
+	if err != nil || (cp != 'C' && cp != 'P') {
+		return occ
+	}
+	kind := "call"
+	if cp == 'P' {
+		kind = "put"
+	}
+	return fmt.Sprintf("%s %s %s", root, strconv.FormatFloat(strike/1000, 'f', -1, 64), kind)
+}